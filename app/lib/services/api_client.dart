@@ -3,6 +3,49 @@ import 'package:http/http.dart' as http;
 import '../config/constants.dart';
 import '../models/parcel.dart';
 
+/// Error returned by the backend in its JSON error envelope:
+/// `{"error": {"code", "message", "requestId", "upstreamStatus", "details"}}`.
+class ApiException implements Exception {
+  final int statusCode;
+  final String code;
+  final String message;
+  final String? requestId;
+  final int? upstreamStatus;
+
+  ApiException({
+    required this.statusCode,
+    required this.code,
+    required this.message,
+    this.requestId,
+    this.upstreamStatus,
+  });
+
+  factory ApiException.fromResponse(http.Response response) {
+    try {
+      final body = jsonDecode(response.body) as Map<String, dynamic>;
+      final error = body['error'] as Map<String, dynamic>;
+      return ApiException(
+        statusCode: response.statusCode,
+        code: error['code'] as String? ?? 'unknown',
+        message: error['message'] as String? ?? '',
+        requestId: error['requestId'] as String?,
+        upstreamStatus: error['upstreamStatus'] as int?,
+      );
+    } catch (_) {
+      return ApiException(
+        statusCode: response.statusCode,
+        code: 'unknown',
+        message: response.body,
+      );
+    }
+  }
+
+  bool get isNotFound => statusCode == 404;
+
+  @override
+  String toString() => 'ApiException($statusCode $code): $message';
+}
+
 class ApiClient {
   final String baseUrl;
   final http.Client _client;
@@ -42,9 +85,7 @@ class ApiClient {
         jsonDecode(response.body) as Map<String, dynamic>,
       );
     }
-    throw Exception(
-      'Parcel search failed (${response.statusCode})',
-    );
+    throw ApiException.fromResponse(response);
   }
 
   void dispose() {
@@ -0,0 +1,58 @@
+// Command cuzk-mock serves a local stand-in for the CUZK REST API.
+//
+// Point the backend at it with CUZK_BASE_URL=http://localhost:8081.
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"katastr-p6/backend/internal/cuzkmock"
+	"katastr-p6/backend/internal/middleware"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "listen address")
+	apiKey := flag.String("api-key", os.Getenv("CUZK_API_KEY"), "required Api-Key header value (empty disables the check)")
+	fixtures := flag.String("fixtures", "", "path to a fixture JSON file (default: embedded Prague 6 dataset)")
+	faultStatus := flag.Int("fault-status", http.StatusServiceUnavailable, "HTTP status for injected faults")
+	faultRate := flag.Float64("fault-rate", 0, "probability (0-1) of failing a request with -fault-status")
+	faultRetryAfter := flag.Int("fault-retry-after", 0, "Retry-After seconds sent with injected faults (0 omits the header)")
+	flag.Parse()
+
+	data := cuzkmock.Prague6()
+	if *fixtures != "" {
+		var err error
+		data, err = cuzkmock.LoadDataset(*fixtures)
+		if err != nil {
+			slog.Error("load fixtures", "path", *fixtures, "error", err)
+			os.Exit(1)
+		}
+	}
+
+	mock := cuzkmock.New(*apiKey, data)
+	if *faultRate > 0 {
+		mock.InjectFault(cuzkmock.Fault{Status: *faultStatus, Rate: *faultRate, RetryAfter: *faultRetryAfter})
+	}
+
+	srv := &http.Server{
+		Addr:         *addr,
+		Handler:      middleware.Logger(mock),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	slog.Info("cuzk mock starting",
+		"addr", *addr,
+		"parcels", len(data.Parcels),
+		"apiKeyRequired", *apiKey != "",
+		"faultRate", *faultRate,
+	)
+	if err := srv.ListenAndServe(); err != nil {
+		slog.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+}
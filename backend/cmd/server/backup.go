@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/store"
+)
+
+// runBackup implements "server backup": a copy of the embedded database
+// written to -o, or stdout. The server holds the database lock while running;
+// back up a live server with GET /api/admin/store/backup instead.
+func runBackup(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("o", "-", "backup file, - for stdout")
+	fs.Parse(args)
+
+	if cfg.StorePath == storeDisabled {
+		slog.Error("STORE_PATH is none, nothing to back up")
+		return 1
+	}
+	db, err := store.Open(cfg.StorePath)
+	if err != nil {
+		logStoreError("open store", err, "the server is running, use GET /api/admin/store/backup")
+		return 1
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			slog.Error("create backup file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	n, err := db.Backup(w)
+	if err != nil {
+		slog.Error("backup failed", "error", err)
+		return 1
+	}
+	slog.Info("backup written", "path", *out, "bytes", n)
+	return 0
+}
+
+// runRestore implements "server restore": replaces the embedded database with
+// the backup read from -i, or stdin. The server must be stopped.
+func runRestore(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	in := fs.String("i", "-", "backup file, - for stdin")
+	fs.Parse(args)
+
+	if cfg.StorePath == storeDisabled {
+		slog.Error("STORE_PATH is none, nowhere to restore to")
+		return 1
+	}
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			slog.Error("open backup file", "error", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+	if err := store.Restore(cfg.StorePath, r); err != nil {
+		logStoreError("restore failed", err, "stop the server first")
+		return 1
+	}
+	slog.Info("store restored", "path", cfg.StorePath, "from", *in)
+	return 0
+}
+
+// logStoreError logs err, with hint when another process holds the database.
+func logStoreError(msg string, err error, hint string) {
+	if errors.Is(err, store.ErrLocked) {
+		slog.Error(msg, "error", err, "hint", hint)
+		return
+	}
+	slog.Error(msg, "error", err)
+}
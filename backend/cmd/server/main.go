@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
@@ -16,44 +17,83 @@ import (
 	"katastr-p6/backend/internal/config"
 	"katastr-p6/backend/internal/cuzk"
 	"katastr-p6/backend/internal/handler"
+	"katastr-p6/backend/internal/history"
 	"katastr-p6/backend/internal/middleware"
+	"katastr-p6/backend/internal/watchlist"
+	"katastr-p6/backend/internal/webhook"
 )
 
 func main() {
 	cfg := config.Load()
 
-	// Redis (optional — graceful fallback)
-	var redisCache *cache.RedisCache
-	if cfg.RedisURL != "" {
-		redisCache = cache.NewRedisCache(cfg.RedisURL)
-		defer redisCache.Close()
-
-		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
-		if err := redisCache.Ping(ctx); err != nil {
-			slog.Warn("redis not available, running without cache", "error", err)
-			redisCache = nil
-		} else {
-			slog.Info("redis connected", "addr", cfg.RedisURL)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "warm":
+			os.Exit(runWarm(cfg, os.Args[2:]))
+		case "backup":
+			os.Exit(runBackup(cfg, os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(cfg, os.Args[2:]))
+		case "import-vfk":
+			os.Exit(runImportVFK(cfg, os.Args[2:]))
+		default:
+			slog.Error("unknown command", "command", os.Args[1], "usage", "server [warm|backup|restore|import-vfk]")
+			os.Exit(2)
 		}
 	}
 
-	// CUZK API client
-	cuzkClient := cuzk.NewClient(cfg.CUZKBaseURL, cfg.CUZKAPIKey)
-	if cfg.CUZKAPIKey == "" {
-		slog.Warn("CUZK_API_KEY not set, API calls to CUZK will fail")
+	cacheStore := newCacheStore(cfg)
+	if cacheStore != nil {
+		defer cacheStore.Close()
+	}
+
+	st, err := openState(cfg, cacheStore)
+	if err != nil {
+		slog.Error("open store", "error", err)
+		os.Exit(1)
 	}
+	defer st.Close()
+
+	cuzkClient := newCUZKClient(cfg, st)
 
 	// Handlers
-	healthHandler := handler.NewHealthHandler(redisCache)
-	parcelHandler := handler.NewParcelHandler(cuzkClient, redisCache)
-	buildingHandler := handler.NewBuildingHandler(cuzkClient, redisCache)
-	unitHandler := handler.NewUnitHandler(cuzkClient, redisCache)
-	proceedingHandler := handler.NewProceedingHandler(cuzkClient, redisCache)
+	cached := handler.NewCachedHandler(cacheStore, cfg.CacheTTLs)
+	healthHandler := handler.NewHealthHandler(cacheStore, cfg.CacheBackend, cached, cuzkClient)
+	parcelHandler := handler.NewParcelHandler(cuzkClient, cached)
+	buildingHandler := handler.NewBuildingHandler(cuzkClient, cached)
+	unitHandler := handler.NewUnitHandler(cuzkClient, cached)
+	proceedingHandler := handler.NewProceedingHandler(cuzkClient, cached)
+	ownershipSheetHandler := handler.NewOwnershipSheetHandler(cuzkClient, cached)
+	historyHandler := handler.NewHistoryHandler(st.history)
+	adminHandler := handler.NewAdminHandler(cacheStore, cfg.AdminToken, cuzkClient)
+	dispatcher := webhook.NewDispatcher(st.webhooks,
+		webhook.WithRetry(cfg.WebhookMaxAttempts, cfg.WebhookBackoff),
+		webhook.WithHTTPClient(&http.Client{Timeout: cfg.WebhookTimeout}),
+	)
+	webhookHandler := handler.NewWebhookHandler(st.webhooks, dispatcher)
+	monitor := watchlist.NewMonitor(cuzkClient, st.watchlist, dispatcher)
+	watchlistHandler := handler.NewWatchlistHandler(st.watchlist, monitor)
+
+	// Background jobs stop when the server shuts down.
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if cfg.WarmAt != "" {
+		w := newWarmer(cfg, parcelHandler, st.warmer)
+		if err := w.Schedule(jobsCtx, cfg.WarmAt); err != nil {
+			slog.Error("cache warming not scheduled", "error", err)
+		}
+	}
+	if cfg.WatchlistInterval > 0 {
+		monitor.Schedule(jobsCtx, cfg.WatchlistInterval)
+	}
+	dispatcher.Start(jobsCtx)
 
 	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
 	r.Use(cors.Handler(middleware.CORS()))
+	r.NotFound(handler.NotFound)
 
 	r.Get("/health", healthHandler.Health)
 	r.Route("/api", func(r chi.Router) {
@@ -64,17 +104,67 @@ func main() {
 		r.Get("/parcels/polygon", parcelHandler.Polygon)
 		r.Get("/parcels/neighbors/{id}", parcelHandler.Neighbors)
 		r.Get("/parcels/{id}", parcelHandler.Get)
+		r.Get("/parcels/{id}/rights", parcelHandler.Rights)
+		r.Get("/parcels/{id}/proceedings", proceedingHandler.ForParcel)
+		r.Get("/parcels/{id}/history", historyHandler.ParcelHistory)
+		r.Get("/parcels/{id}/diff", historyHandler.ParcelDiff)
 
 		// Buildings
 		r.Get("/buildings/search", buildingHandler.Search)
 		r.Get("/buildings/{id}", buildingHandler.Get)
+		r.Get("/buildings/{id}/rights", buildingHandler.Rights)
+		r.Get("/buildings/{id}/proceedings", proceedingHandler.ForBuilding)
+		r.Get("/buildings/{id}/history", historyHandler.BuildingHistory)
+		r.Get("/buildings/{id}/diff", historyHandler.BuildingDiff)
 
 		// Units
 		r.Get("/units/search", unitHandler.Search)
 		r.Get("/units/{id}", unitHandler.Get)
+		r.Get("/units/{id}/rights", unitHandler.Rights)
+		r.Get("/units/{id}/proceedings", proceedingHandler.ForUnit)
+		r.Get("/units/{id}/history", historyHandler.UnitHistory)
+		r.Get("/units/{id}/diff", historyHandler.UnitDiff)
 
 		// Proceedings
+		r.Get("/proceedings/search", proceedingHandler.Search)
 		r.Get("/proceedings/{id}", proceedingHandler.Get)
+
+		// Ownership sheets (LV)
+		r.Get("/ownership-sheets/{area}/{number}", ownershipSheetHandler.Get)
+
+		// Watchlist
+		r.Get("/watchlist", watchlistHandler.List)
+		r.Get("/watchlist/events", watchlistHandler.Events)
+		r.Get("/watchlist/{kind}/{id}", watchlistHandler.Get)
+		r.Put("/watchlist/{kind}/{id}", watchlistHandler.Put)
+		r.Delete("/watchlist/{kind}/{id}", watchlistHandler.Delete)
+		r.Get("/watchlist/{kind}/{id}/events", watchlistHandler.Events)
+		r.Post("/watchlist/{kind}/{id}/check", watchlistHandler.Check)
+
+		// Admin
+		if cfg.AdminToken == "" {
+			slog.Warn("ADMIN_TOKEN not set, admin endpoints disabled")
+			return
+		}
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(adminHandler.Authenticate)
+			r.Get("/cache/keys", adminHandler.Keys)
+			r.Get("/cache/entry", adminHandler.Entry)
+			r.Delete("/cache", adminHandler.Purge)
+			r.Delete("/cache/{entity}/{id}", adminHandler.PurgeEntity)
+			r.Get("/cuzk/keys", adminHandler.APIKeys)
+
+			r.Get("/webhooks", webhookHandler.Subscriptions)
+			r.Post("/webhooks", webhookHandler.Subscribe)
+			r.Delete("/webhooks/{id}", webhookHandler.Unsubscribe)
+			r.Get("/webhooks/deliveries", webhookHandler.Deliveries)
+			r.Get("/webhooks/deliveries/{id}", webhookHandler.Delivery)
+			r.Post("/webhooks/deliveries/{id}/retry", webhookHandler.Redeliver)
+
+			if st.db != nil {
+				r.Get("/store/backup", handler.NewStoreHandler(st.db).Backup)
+			}
+		})
 	})
 
 	srv := &http.Server{
@@ -99,6 +189,7 @@ func main() {
 
 	<-done
 	slog.Info("shutting down...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -106,3 +197,86 @@ func main() {
 		slog.Error("shutdown error", "error", err)
 	}
 }
+
+// newCacheStore builds the cache backend selected by cfg.CacheBackend.
+// Returns nil when caching is disabled.
+func newCacheStore(cfg *config.Config) cache.Store {
+	memory := func() *cache.MemoryCache {
+		return cache.NewMemoryCache(cfg.CacheMaxEntries, cfg.CacheMaxTTL)
+	}
+
+	switch cfg.CacheBackend {
+	case cache.BackendNone:
+		slog.Info("cache disabled")
+		return nil
+	case cache.BackendMemory:
+		slog.Info("using in-memory cache", "maxEntries", cfg.CacheMaxEntries)
+		return memory()
+	case cache.BackendRedis, cache.BackendTiered:
+		if cfg.RedisURL == "" {
+			slog.Warn("REDIS_URL not set, using in-memory cache")
+			return memory()
+		}
+	default:
+		slog.Warn("unknown CACHE_BACKEND, using in-memory cache", "backend", cfg.CacheBackend)
+		return memory()
+	}
+
+	redisCache := cache.NewRedisCache(cfg.RedisURL)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	redisErr := redisCache.Ping(ctx)
+
+	if cfg.CacheBackend == cache.BackendTiered {
+		// Keep Redis as L2 even if it is down now; the client reconnects on its own.
+		if redisErr != nil {
+			slog.Warn("redis not available, serving from in-memory L1 until it recovers", "error", redisErr)
+		} else {
+			slog.Info("redis connected", "addr", cfg.RedisURL)
+		}
+		return cache.NewTieredCache(memory(), redisCache, cfg.CacheL1TTL)
+	}
+
+	if redisErr != nil {
+		slog.Warn("redis not available, falling back to in-memory cache", "error", redisErr)
+		redisCache.Close()
+		return memory()
+	}
+	slog.Info("redis connected", "addr", cfg.RedisURL)
+	return redisCache
+}
+
+// newCUZKClient builds the CUZK API client, optionally recording or replaying fixtures.
+// Per-key usage is persisted and fetched objects are recorded in st; objects
+// imported from VFK extracts are served when CUZK fails.
+func newCUZKClient(cfg *config.Config, st *state) *cuzk.Client {
+	keys := append([]string{cfg.CUZKAPIKey}, cfg.CUZKAPIKeys...)
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	opts := []cuzk.Option{
+		cuzk.WithAPIKeys(keys, cfg.CUZKKeyDailyQuota, st.usage),
+		cuzk.WithRateLimit(cfg.CUZKRateLimit, cfg.CUZKRateBurst),
+		cuzk.WithCircuitBreaker(cfg.CUZKBreakerThreshold, cfg.CUZKBreakerOpenTimeout),
+		cuzk.WithObserver(history.NewRecorder(st.history)),
+	}
+	if st.local != nil {
+		opts = append(opts, cuzk.WithLocalSource(st.local))
+	}
+	switch cfg.CUZKFixturesMode {
+	case "record":
+		opts = append(opts, cuzk.WithRecording(cfg.CUZKFixturesDir))
+		slog.Info("recording CUZK fixtures", "dir", cfg.CUZKFixturesDir)
+	case "replay":
+		opts = append(opts, cuzk.WithReplay(cfg.CUZKFixturesDir))
+		slog.Info("replaying CUZK fixtures, upstream disabled", "dir", cfg.CUZKFixturesDir)
+	case "":
+	default:
+		slog.Warn("unknown CUZK_FIXTURES_MODE, ignoring", "mode", cfg.CUZKFixturesMode)
+	}
+
+	client := cuzk.NewClient(cfg.CUZKBaseURL, cfg.CUZKAPIKey, opts...)
+	if cfg.CUZKAPIKey == "" && len(cfg.CUZKAPIKeys) == 0 {
+		slog.Warn("CUZK_API_KEY not set, API calls to CUZK will fail")
+	}
+	return client
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"log/slog"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/history"
+	"katastr-p6/backend/internal/store"
+	"katastr-p6/backend/internal/warmer"
+	"katastr-p6/backend/internal/watchlist"
+	"katastr-p6/backend/internal/webhook"
+)
+
+// storeDisabled as STORE_PATH keeps state in the JSON file stores.
+const storeDisabled = "none"
+
+// checkpointStore is a warmer.StateStore whose checkpoint can be discarded.
+type checkpointStore interface {
+	warmer.StateStore
+	Reset() error
+}
+
+// state holds the stores for everything that must survive a restart.
+type state struct {
+	db        *store.DB // nil when STORE_PATH is none
+	watchlist watchlist.Store
+	webhooks  webhook.Store
+	history   history.Store
+	warmer    checkpointStore
+	usage     cuzk.UsageStore
+	local     cuzk.LocalSource // data imported with "server import-vfk"; nil without the store
+}
+
+// openState opens the embedded database at cfg.StorePath, importing the JSON
+// file stores on the first open, or falls back to the file stores with API
+// key usage kept in cacheStore.
+func openState(cfg *config.Config, cacheStore cache.Store) (*state, error) {
+	if cfg.StorePath == storeDisabled {
+		s := &state{
+			watchlist: watchlist.NewFileStore(cfg.WatchlistPath),
+			webhooks:  webhook.NewFileStore(cfg.WebhookPath),
+			history:   history.NewFileStore(cfg.HistoryDir),
+			warmer:    warmer.NewFileStateStore(cfg.WarmStatePath),
+		}
+		if cacheStore != nil {
+			s.usage = cacheStore
+		}
+		slog.Info("embedded store disabled, using file stores")
+		return s, nil
+	}
+
+	db, err := store.Open(cfg.StorePath)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("embedded store opened", "path", db.Path(), "schemaVersion", store.SchemaVersion())
+
+	// Carry over what earlier versions kept in the file stores.
+	imported, err := db.ImportFiles(store.FileStores{
+		Watchlist: watchlist.NewFileStore(cfg.WatchlistPath),
+		Webhooks:  webhook.NewFileStore(cfg.WebhookPath),
+		History:   history.NewFileStore(cfg.HistoryDir),
+		Warmer:    warmer.NewFileStateStore(cfg.WarmStatePath),
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if imported != (store.ImportCounts{}) {
+		slog.Info("imported file stores into the embedded store, the files can be removed",
+			"watches", imported.Items, "events", imported.Events, "subscriptions", imported.Subscriptions,
+			"deliveries", imported.Deliveries, "objectsWithHistory", imported.Objects, "checkpoint", imported.Checkpoint)
+	}
+	return &state{
+		db:        db,
+		watchlist: db.Watchlist(),
+		webhooks:  db.Webhooks(),
+		history:   db.History(),
+		warmer:    db.Warmer(),
+		usage:     db.Usage(),
+		local:     db.Local(),
+	}, nil
+}
+
+// Close closes the embedded database, if any.
+func (s *state) Close() {
+	if s.db == nil {
+		return
+	}
+	if err := s.db.Close(); err != nil {
+		slog.Error("close store", "error", err)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/store"
+	"katastr-p6/backend/internal/vfk"
+)
+
+// runImportVFK implements "server import-vfk": loads VFK extracts into the
+// embedded store, from where the server answers parcel, building, unit and
+// ownership sheet requests that CUZK fails, e.g. without an API key.
+// -replace drops data from earlier imports first. The server must be stopped.
+func runImportVFK(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("import-vfk", flag.ExitOnError)
+	replace := fs.Bool("replace", false, "drop previously imported data first")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: server import-vfk [-replace] file.vfk...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	if cfg.StorePath == storeDisabled {
+		slog.Error("importing VFK needs the embedded store, STORE_PATH is none")
+		return 1
+	}
+	db, err := store.Open(cfg.StorePath)
+	if err != nil {
+		logStoreError("open store", err, "stop the server first")
+		return 1
+	}
+	defer db.Close()
+
+	for i, path := range fs.Args() {
+		x, err := readVFK(path)
+		if err != nil {
+			slog.Error("read VFK", "path", path, "error", err)
+			return 1
+		}
+		if err := db.Local().Import(x, *replace && i == 0); err != nil {
+			slog.Error("import VFK", "path", path, "error", err)
+			return 1
+		}
+		slog.Info("VFK imported", "path", path, "created", x.Created,
+			"parcels", len(x.Parcels), "buildings", len(x.Buildings),
+			"units", len(x.Units), "ownershipSheets", len(x.OwnershipSheets))
+	}
+
+	counts, err := db.Local().Counts()
+	if err != nil {
+		slog.Error("count local data", "error", err)
+		return 1
+	}
+	slog.Info("local data", "parcels", counts.Parcels, "buildings", counts.Buildings,
+		"units", counts.Units, "ownershipSheets", counts.OwnershipSheets)
+	return 0
+}
+
+func readVFK(path string) (*vfk.Extract, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return vfk.Read(f)
+}
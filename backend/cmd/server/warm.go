@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/handler"
+	"katastr-p6/backend/internal/warmer"
+)
+
+// runWarm implements "server warm": one cache warming run that resumes an
+// interrupted run unless -reset is given. Ctrl-C saves the checkpoint.
+// It writes straight to Redis, since a memory cache would be gone when the
+// command exits.
+func runWarm(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("warm", flag.ExitOnError)
+	reset := fs.Bool("reset", false, "ignore the saved checkpoint and start over")
+	fs.Parse(args)
+
+	if cfg.CacheBackend != cache.BackendRedis && cfg.CacheBackend != cache.BackendTiered || cfg.RedisURL == "" {
+		slog.Error("cache warming needs the shared Redis cache, set CACHE_BACKEND=redis or tiered and REDIS_URL",
+			"backend", cfg.CacheBackend)
+		return 1
+	}
+	cacheStore := cache.NewRedisCache(cfg.RedisURL)
+	defer cacheStore.Close()
+	pingCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	err := cacheStore.Ping(pingCtx)
+	cancel()
+	if err != nil {
+		slog.Error("cache warming needs the shared Redis cache, redis not available", "addr", cfg.RedisURL, "error", err)
+		return 1
+	}
+
+	// The server holds the embedded store while running; schedule warming
+	// there with WARM_AT instead.
+	st, err := openState(cfg, cacheStore)
+	if err != nil {
+		logStoreError("open store", err, "the server is running, warm it with WARM_AT")
+		return 1
+	}
+	defer st.Close()
+
+	cached := handler.NewCachedHandler(cacheStore, cfg.CacheTTLs)
+	parcelHandler := handler.NewParcelHandler(newCUZKClient(cfg, st), cached)
+
+	if *reset {
+		if err := st.warmer.Reset(); err != nil {
+			slog.Error("reset checkpoint", "error", err)
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newWarmer(cfg, parcelHandler, st.warmer).Run(ctx); err != nil {
+		if errors.Is(err, context.Canceled) {
+			slog.Info("cache warming interrupted, run again to resume")
+			return 130
+		}
+		slog.Error("cache warming failed", "error", err)
+		return 1
+	}
+	return 0
+}
+
+func newWarmer(cfg *config.Config, target warmer.Target, state warmer.StateStore) *warmer.Warmer {
+	return warmer.New(target, state, warmer.Plan{
+		Areas: cfg.WarmAreas,
+		From:  cfg.WarmParcelFrom,
+		To:    cfg.WarmParcelTo,
+	})
+}
@@ -8,10 +8,13 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/redis/go-redis/v9 v9.17.3
 	github.com/wroge/wgs84/v2 v2.0.0-alpha.13
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/text v0.28.0
 	golang.org/x/time v0.14.0
 )
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	golang.org/x/sys v0.29.0 // indirect
 )
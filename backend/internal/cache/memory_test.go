@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(2, 0)
+
+	c.Set(ctx, "a", "1", time.Minute)
+	c.Set(ctx, "b", "2", time.Minute)
+	c.Get(ctx, "a") // a is now more recent than b
+	c.Set(ctx, "c", "3", time.Minute)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("expected b to be evicted, got %v", err)
+	}
+	if v, err := c.Get(ctx, "a"); err != nil || v != "1" {
+		t.Errorf("expected a=1, got %q, %v", v, err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", c.Len())
+	}
+}
+
+func TestMemoryCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10, 20*time.Millisecond)
+
+	c.Set(ctx, "k", "v", time.Hour) // capped to maxTTL
+	time.Sleep(30 * time.Millisecond)
+
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ErrMiss) {
+		t.Errorf("expected expired entry to miss, got %v", err)
+	}
+}
+
+func TestTieredCacheSurvivesL2Miss(t *testing.T) {
+	ctx := context.Background()
+	l1, l2 := NewMemoryCache(10, 0), NewMemoryCache(10, 0)
+	c := NewTieredCache(l1, l2, time.Minute)
+
+	l2.Set(ctx, "k", "v", time.Hour)
+	if v, err := c.Get(ctx, "k"); err != nil || v != "v" {
+		t.Fatalf("expected L2 hit, got %q, %v", v, err)
+	}
+	if v, err := l1.Get(ctx, "k"); err != nil || v != "v" {
+		t.Errorf("expected L2 hit to populate L1, got %q, %v", v, err)
+	}
+}
@@ -2,11 +2,14 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisCache is a Store backed by Redis.
 type RedisCache struct {
 	client *redis.Client
 }
@@ -23,13 +26,50 @@ func (c *RedisCache) Ping(ctx context.Context) error {
 }
 
 func (c *RedisCache) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	v, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrMiss
+	}
+	return v, err
 }
 
 func (c *RedisCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
 	return c.client.Set(ctx, key, value, ttl).Err()
 }
 
+func (c *RedisCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, globEscape(prefix)+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+func (c *RedisCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := c.client.TTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	// -2 = key does not exist, -1 = no expiry
+	if ttl == -2 {
+		return 0, ErrMiss
+	}
+	return ttl, nil
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
 func (c *RedisCache) Close() error {
 	return c.client.Close()
 }
+
+// globEscape escapes Redis glob metacharacters so prefix matches literally.
+func globEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(s)
+}
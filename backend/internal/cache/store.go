@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by Store.Get when the key is absent or expired.
+var ErrMiss = errors.New("cache: miss")
+
+// Backend names accepted by config.Config.CacheBackend.
+const (
+	BackendNone   = "none"
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendTiered = "tiered"
+)
+
+// Store is a string key/value cache with per-entry TTL.
+type Store interface {
+	// Get returns the cached value or ErrMiss.
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	// Keys returns all keys starting with prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	// TTL returns the remaining lifetime of key or ErrMiss.
+	TTL(ctx context.Context, key string) (time.Duration, error)
+	// Delete removes keys; missing keys are ignored.
+	Delete(ctx context.Context, keys ...string) error
+	Ping(ctx context.Context) error
+	Close() error
+}
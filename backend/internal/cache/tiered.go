@@ -0,0 +1,197 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Backoff between L2 probes while it is failing.
+const (
+	l2MinBackoff = time.Second
+	l2MaxBackoff = 30 * time.Second
+)
+
+// ErrL2Unavailable is returned by operations that need L2 while it is failing.
+var ErrL2Unavailable = errors.New("cache: l2 unavailable")
+
+// TieredCache serves reads from an in-memory L1 in front of a shared L2 (Redis).
+// L1 entries live at most l1TTL so instances converge on L2 state.
+// When L2 fails, it is skipped until a probe after a growing backoff succeeds;
+// meanwhile L1 serves alone and keeps entries for their full TTL.
+type TieredCache struct {
+	l1     Store
+	l2     Store
+	l1TTL  time.Duration
+	health l2Health
+}
+
+// NewTieredCache creates a two-tier Store.
+func NewTieredCache(l1, l2 Store, l1TTL time.Duration) *TieredCache {
+	return &TieredCache{l1: l1, l2: l2, l1TTL: l1TTL}
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string) (string, error) {
+	if v, err := c.l1.Get(ctx, key); err == nil {
+		return v, nil
+	}
+	if !c.health.available() {
+		return "", ErrMiss
+	}
+
+	v, err := c.l2.Get(ctx, key)
+	if errors.Is(err, ErrMiss) {
+		c.health.record(nil)
+		return "", ErrMiss
+	}
+	c.health.record(err)
+	if err != nil {
+		slog.Warn("cache l2 get error", "key", key, "error", err)
+		return "", ErrMiss
+	}
+
+	if ttl := c.l1Refill(ctx, key); ttl > 0 {
+		c.l1.Set(ctx, key, v, ttl)
+	}
+	return v, nil
+}
+
+// l1Refill returns how long an entry read from L2 may live in L1: l1TTL, but
+// never longer than the entry has left in L2, so L1 cannot outlive it.
+func (c *TieredCache) l1Refill(ctx context.Context, key string) time.Duration {
+	remaining, err := c.l2.TTL(ctx, key)
+	switch {
+	case errors.Is(err, ErrMiss):
+		return 0 // expired since the read
+	case err != nil:
+		c.health.record(err)
+		return c.l1TTL
+	case remaining <= 0: // no expiry in L2
+		return c.l1TTL
+	}
+	return min(remaining, c.l1TTL)
+}
+
+// Set writes both tiers. While L2 is failing the entry goes to L1 only, with
+// its full TTL, so the cache keeps working until L2 recovers.
+func (c *TieredCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	if !c.health.available() {
+		return c.l1.Set(ctx, key, value, ttl)
+	}
+	err := c.l2.Set(ctx, key, value, ttl)
+	c.health.record(err)
+	if err != nil {
+		slog.Warn("cache l2 set error", "key", key, "error", err)
+		return c.l1.Set(ctx, key, value, ttl)
+	}
+	return c.l1.Set(ctx, key, value, min(ttl, c.l1TTL))
+}
+
+// Keys merges the keys of both tiers. If L2 is unavailable only L1 keys are returned.
+func (c *TieredCache) Keys(ctx context.Context, prefix string) ([]string, error) {
+	l1Keys, _ := c.l1.Keys(ctx, prefix)
+	if !c.health.available() {
+		return l1Keys, nil
+	}
+	l2Keys, err := c.l2.Keys(ctx, prefix)
+	c.health.record(err)
+	if err != nil {
+		slog.Warn("cache l2 keys error", "prefix", prefix, "error", err)
+		return l1Keys, nil
+	}
+
+	seen := make(map[string]bool, len(l2Keys))
+	for _, k := range l2Keys {
+		seen[k] = true
+	}
+	for _, k := range l1Keys {
+		if !seen[k] {
+			l2Keys = append(l2Keys, k)
+		}
+	}
+	return l2Keys, nil
+}
+
+// TTL reports the L2 lifetime, falling back to L1 for entries only held in memory.
+func (c *TieredCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	if c.health.available() {
+		ttl, err := c.l2.TTL(ctx, key)
+		if err == nil || errors.Is(err, ErrMiss) {
+			c.health.record(nil)
+		} else {
+			c.health.record(err)
+		}
+		if err == nil {
+			return ttl, nil
+		}
+	}
+	return c.l1.TTL(ctx, key)
+}
+
+// Delete removes keys from both tiers. While L2 is failing only L1 is purged
+// and ErrL2Unavailable is returned.
+func (c *TieredCache) Delete(ctx context.Context, keys ...string) error {
+	c.l1.Delete(ctx, keys...)
+	if !c.health.available() {
+		return ErrL2Unavailable
+	}
+	err := c.l2.Delete(ctx, keys...)
+	c.health.record(err)
+	return err
+}
+
+// Ping reports the health of L2; L1 is always available. A successful ping
+// ends an outage without waiting for the backoff.
+func (c *TieredCache) Ping(ctx context.Context) error {
+	err := c.l2.Ping(ctx)
+	c.health.record(err)
+	return err
+}
+
+func (c *TieredCache) Close() error {
+	return errors.Join(c.l1.Close(), c.l2.Close())
+}
+
+// l2Health tracks L2 failures. After a failure L2 is skipped for a backoff
+// that doubles with each failed probe; once it elapses one call probes L2.
+type l2Health struct {
+	mu      sync.Mutex
+	backoff time.Duration // 0 while healthy
+	retryAt time.Time
+}
+
+// available reports whether a call should go to L2. When the backoff has
+// elapsed it lets one caller through as the probe.
+func (h *l2Health) available() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.backoff == 0 {
+		return true
+	}
+	now := time.Now()
+	if now.Before(h.retryAt) {
+		return false
+	}
+	h.retryAt = now.Add(h.backoff)
+	return true
+}
+
+// record updates the state with the outcome of an L2 call.
+func (h *l2Health) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		if h.backoff > 0 {
+			slog.Info("cache l2 recovered")
+		}
+		h.backoff = 0
+		return
+	}
+	if h.backoff == 0 {
+		slog.Warn("cache l2 failing, serving from l1 only", "error", err)
+	}
+	h.backoff = min(max(h.backoff*2, l2MinBackoff), l2MaxBackoff)
+	h.retryAt = time.Now().Add(h.backoff)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// downStore is an L2 whose every call fails, counting the attempts.
+type downStore struct {
+	Store
+	calls int
+}
+
+func (s *downStore) fail() error {
+	s.calls++
+	return errors.New("dial tcp: connection refused")
+}
+
+func (s *downStore) Get(context.Context, string) (string, error)              { return "", s.fail() }
+func (s *downStore) Set(context.Context, string, string, time.Duration) error { return s.fail() }
+
+func TestTieredCacheKeepsFullTTLAndSkipsFailingL2(t *testing.T) {
+	ctx := context.Background()
+	l2 := &downStore{}
+	c := NewTieredCache(NewMemoryCache(10, 0), l2, time.Second)
+
+	c.Set(ctx, "k", "v", time.Hour)
+	if ttl, err := c.TTL(ctx, "k"); err != nil || ttl < time.Minute {
+		t.Fatalf("expected the full TTL in L1 while L2 is down, got %v, %v", ttl, err)
+	}
+	for range 10 {
+		c.Get(ctx, "missing")
+	}
+	if l2.calls != 1 {
+		t.Fatalf("expected L2 to be skipped after the first failure, got %d calls", l2.calls)
+	}
+	if v, err := c.Get(ctx, "k"); err != nil || v != "v" {
+		t.Fatalf("expected k from L1, got %q, %v", v, err)
+	}
+}
+
+func TestTieredCacheRefillsL1WithinL2Lifetime(t *testing.T) {
+	ctx := context.Background()
+	l2 := NewMemoryCache(10, 0)
+	c := NewTieredCache(NewMemoryCache(10, 0), l2, time.Hour)
+
+	l2.Set(ctx, "k", "v", time.Minute)
+	if v, err := c.Get(ctx, "k"); err != nil || v != "v" {
+		t.Fatalf("expected k from L2, got %q, %v", v, err)
+	}
+	if ttl, err := c.l1.TTL(ctx, "k"); err != nil || ttl > time.Minute {
+		t.Errorf("L1 refill lives %v (%v), want at most the minute left in L2", ttl, err)
+	}
+}
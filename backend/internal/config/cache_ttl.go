@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// CacheTTL is the cache lifetime policy for one key prefix.
+//
+// Soft is how long an entry is fresh; Hard is how long a stale entry may still
+// be served; Negative is how long "not found" and empty results are cached.
+type CacheTTL struct {
+	Soft     time.Duration
+	Hard     time.Duration
+	Negative time.Duration
+}
+
+// CacheTTLPolicy maps cache key prefixes ("parcel", "parcels:search", ...) to TTLs.
+// The "default" entry applies to prefixes without their own entry.
+type CacheTTLPolicy map[string]CacheTTL
+
+// For returns the TTL for prefix, falling back to the "default" entry.
+func (p CacheTTLPolicy) For(prefix string) CacheTTL {
+	if ttl, ok := p[prefix]; ok {
+		return ttl
+	}
+	return p["default"]
+}
+
+// DefaultCacheTTLs reflects how often CUZK data changes: cadastral objects
+// rarely, proceeding status often.
+func DefaultCacheTTLs() CacheTTLPolicy {
+	cadastral := CacheTTL{Soft: time.Hour, Hard: 7 * 24 * time.Hour, Negative: 10 * time.Minute}
+	search := CacheTTL{Soft: time.Hour, Hard: 7 * 24 * time.Hour, Negative: 5 * time.Minute}
+	// Rights change with every recorded proceeding, so they go stale sooner.
+	rights := CacheTTL{Soft: 15 * time.Minute, Hard: 24 * time.Hour, Negative: 5 * time.Minute}
+	proceeding := CacheTTL{Soft: time.Minute, Hard: time.Hour, Negative: time.Minute}
+
+	return CacheTTLPolicy{
+		"default":               {Soft: 5 * time.Minute, Hard: 24 * time.Hour, Negative: time.Minute},
+		"parcel":                cadastral,
+		"parcels:search":        search,
+		"parcels:polygon":       search,
+		"parcels:neighbors":     cadastral,
+		"parcels:rights":        rights,
+		"parcels:proceedings":   proceeding,
+		"building":              cadastral,
+		"buildings:search":      search,
+		"buildings:rights":      rights,
+		"buildings:proceedings": proceeding,
+		"unit":                  cadastral,
+		"units:search":          search,
+		"units:rights":          rights,
+		"units:proceedings":     proceeding,
+		"ownership-sheet":       cadastral,
+		"proceeding":            proceeding,
+		"proceedings:search":    proceeding,
+		// The operations timeline moves with the status; the parties, affected
+		// objects and filed documents are mostly fixed once the proceeding is filed.
+		"proceeding:operations":   proceeding,
+		"proceeding:participants": rights,
+		"proceeding:objects":      rights,
+		"proceeding:documents":    rights,
+	}
+}
+
+// loadCacheTTLs starts from the defaults, applies the JSON file at path (if any)
+// and then per-prefix env overrides.
+//
+// File format: {"parcel": {"soft": "1h", "hard": "168h", "negative": "10m"}, ...}
+//
+// Env format: CACHE_TTL_<PREFIX>=soft,hard,negative where PREFIX is upper-cased
+// with ':' replaced by '_', e.g. CACHE_TTL_PARCELS_SEARCH=30m,24h,5m.
+func loadCacheTTLs(path string) CacheTTLPolicy {
+	policy := DefaultCacheTTLs()
+
+	if path != "" {
+		if err := applyCacheTTLFile(policy, path); err != nil {
+			slog.Warn("cache TTL file ignored", "path", path, "error", err)
+		}
+	}
+
+	for prefix, ttl := range policy {
+		key := "CACHE_TTL_" + strings.ToUpper(strings.ReplaceAll(prefix, ":", "_"))
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		parsed, err := parseCacheTTL(strings.Split(v, ","), ttl)
+		if err != nil {
+			slog.Warn("invalid cache TTL in env, using default", "key", key, "value", v, "error", err)
+			continue
+		}
+		policy[prefix] = parsed
+	}
+
+	return policy
+}
+
+func applyCacheTTLFile(policy CacheTTLPolicy, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var file map[string]struct {
+		Soft     string `json:"soft"`
+		Hard     string `json:"hard"`
+		Negative string `json:"negative"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("json decode: %w", err)
+	}
+	for prefix, f := range file {
+		ttl, err := parseCacheTTL([]string{f.Soft, f.Hard, f.Negative}, policy.For(prefix))
+		if err != nil {
+			return fmt.Errorf("%s: %w", prefix, err)
+		}
+		policy[prefix] = ttl
+	}
+	return nil
+}
+
+// parseCacheTTL parses soft, hard and negative durations; empty values keep base.
+func parseCacheTTL(values []string, base CacheTTL) (CacheTTL, error) {
+	if len(values) > 3 {
+		return base, fmt.Errorf("expected at most 3 durations, got %d", len(values))
+	}
+	ttl := base
+	fields := []*time.Duration{&ttl.Soft, &ttl.Hard, &ttl.Negative}
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return base, err
+		}
+		*fields[i] = d
+	}
+	if ttl.Hard < ttl.Soft {
+		return base, fmt.Errorf("hard TTL %s shorter than soft TTL %s", ttl.Hard, ttl.Soft)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadCacheTTLsFileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ttl.json")
+	os.WriteFile(path, []byte(`{"parcel": {"soft": "2h"}, "ownership": {"soft": "10m", "hard": "1h"}}`), 0o644)
+	t.Setenv("CACHE_TTL_PARCELS_SEARCH", "30m,,2m")
+
+	policy := loadCacheTTLs(path)
+
+	if got := policy.For("parcel"); got.Soft != 2*time.Hour || got.Hard != 7*24*time.Hour {
+		t.Errorf("parcel: file should override soft only, got %+v", got)
+	}
+	if got := policy.For("ownership"); got.Soft != 10*time.Minute || got.Negative != time.Minute {
+		t.Errorf("ownership: new prefix should inherit default, got %+v", got)
+	}
+	if got := policy.For("parcels:search"); got.Soft != 30*time.Minute || got.Negative != 2*time.Minute {
+		t.Errorf("parcels:search: env override not applied, got %+v", got)
+	}
+	if got := policy.For("unknown"); got != policy["default"] {
+		t.Errorf("unknown prefix should use default, got %+v", got)
+	}
+}
+
+func TestParseCacheTTLRejectsHardBelowSoft(t *testing.T) {
+	if _, err := parseCacheTTL([]string{"2h", "1h"}, CacheTTL{}); err == nil {
+		t.Error("expected error for hard < soft")
+	}
+}
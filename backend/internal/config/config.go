@@ -1,7 +1,11 @@
 package config
 
 import (
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,8 +15,67 @@ type Config struct {
 	RedisURL    string
 	CUZKAPIKey  string
 	CUZKBaseURL string
+
+	// CUZKAPIKeys are extra keys rotated together with CUZKAPIKey, each allowed
+	// CUZKKeyDailyQuota requests per day (0 means unlimited).
+	CUZKAPIKeys       []string
+	CUZKKeyDailyQuota int
+
+	// CUZKRateLimit is the sustained upstream request rate per second (<= 0 disables limiting);
+	// CUZKRateBurst is how many requests may go out back to back.
+	CUZKRateLimit float64
+	CUZKRateBurst int
+
+	// The circuit breaker opens after CUZKBreakerThreshold consecutive failed calls
+	// (<= 0 disables it) and fails fast for CUZKBreakerOpenTimeout before probing again.
+	CUZKBreakerThreshold   int
+	CUZKBreakerOpenTimeout time.Duration
+
+	// CUZKFixturesMode is "record", "replay" or empty (live traffic only).
+	CUZKFixturesMode string
+	CUZKFixturesDir  string
+
+	// CacheBackend is one of "tiered" (memory L1 + Redis L2), "redis", "memory" or "none".
+	CacheBackend    string
+	CacheMaxEntries int           // in-memory LRU capacity
+	CacheMaxTTL     time.Duration // upper bound for in-memory entry TTL
+	CacheL1TTL      time.Duration // how long tiered mode keeps an entry in memory
+	CacheTTLs       CacheTTLPolicy
+
+	// AdminToken protects /api/admin; admin endpoints are disabled when empty.
+	AdminToken string
+
+	// Cache warming: every parcel number in WarmParcelFrom..WarmParcelTo in each of WarmAreas.
+	WarmAreas      []int
+	WarmParcelFrom int
+	WarmParcelTo   int
+	WarmAt         string // daily local time "HH:MM" for the in-server schedule; empty disables
+	WarmStatePath  string // checkpoint file for resuming interrupted runs
+
+	// Watchlist: watched objects are re-checked every WatchlistInterval (0 disables monitoring).
+	WatchlistPath     string
+	WatchlistInterval time.Duration
+
+	// HistoryDir holds every distinct version of the parcels, buildings and units fetched from CUZK.
+	HistoryDir string
+
+	// Webhooks: a delivery is retried up to WebhookMaxAttempts times, waiting
+	// WebhookBackoff before the first retry and doubling it for each further one.
+	WebhookPath        string
+	WebhookMaxAttempts int
+	WebhookBackoff     time.Duration
+	WebhookTimeout     time.Duration
+
+	// StorePath is the embedded database holding the watchlist, webhooks,
+	// history, job checkpoints and API key usage. "none" keeps them in the
+	// JSON files above instead.
+	StorePath string
 }
 
+// prague6Areas are the cadastral area (katastrální území) codes of Prague 6:
+// Dejvice, Bubeneč, Břevnov, Střešovice, Vokovice, Veleslavín, Liboc, Sedlec, Ruzyně.
+var prague6Areas = []int{729272, 730122, 729582, 730955, 731001, 730963, 730751, 730904, 730882}
+
 func Load() *Config {
 	_ = godotenv.Load()
 
@@ -21,6 +84,44 @@ func Load() *Config {
 		RedisURL:    getEnv("REDIS_URL", "localhost:6379"),
 		CUZKAPIKey:  getEnv("CUZK_API_KEY", ""),
 		CUZKBaseURL: getEnv("CUZK_BASE_URL", "https://api-kn.cuzk.gov.cz/api/v1"),
+
+		CUZKAPIKeys:       getEnvList("CUZK_API_KEYS"),
+		CUZKKeyDailyQuota: getEnvInt("CUZK_KEY_DAILY_QUOTA", 0),
+
+		CUZKRateLimit: getEnvFloat("CUZK_RATE_LIMIT", 1),
+		CUZKRateBurst: getEnvInt("CUZK_RATE_BURST", 1),
+
+		CUZKBreakerThreshold:   getEnvInt("CUZK_BREAKER_THRESHOLD", 5),
+		CUZKBreakerOpenTimeout: getEnvDuration("CUZK_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+
+		CUZKFixturesMode: getEnv("CUZK_FIXTURES_MODE", ""),
+		CUZKFixturesDir:  getEnv("CUZK_FIXTURES_DIR", "testdata/cuzk"),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", "tiered"),
+		CacheMaxEntries: getEnvInt("CACHE_MAX_ENTRIES", 10000),
+		CacheMaxTTL:     getEnvDuration("CACHE_MAX_TTL", 7*24*time.Hour),
+		CacheL1TTL:      getEnvDuration("CACHE_L1_TTL", 30*time.Second),
+		CacheTTLs:       loadCacheTTLs(getEnv("CACHE_TTL_FILE", "")),
+
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+		WarmAreas:      getEnvInts("WARM_AREAS", prague6Areas),
+		WarmParcelFrom: getEnvInt("WARM_PARCEL_FROM", 1),
+		WarmParcelTo:   getEnvInt("WARM_PARCEL_TO", 500),
+		WarmAt:         getEnv("WARM_AT", ""),
+		WarmStatePath:  getEnv("WARM_STATE_PATH", "data/warmer-state.json"),
+
+		WatchlistPath:     getEnv("WATCHLIST_PATH", "data/watchlist.json"),
+		WatchlistInterval: getEnvDuration("WATCHLIST_INTERVAL", 15*time.Minute),
+
+		HistoryDir: getEnv("HISTORY_DIR", "data/history"),
+
+		WebhookPath:        getEnv("WEBHOOK_PATH", "data/webhooks.json"),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookBackoff:     getEnvDuration("WEBHOOK_BACKOFF", 30*time.Second),
+		WebhookTimeout:     getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		StorePath: getEnv("STORE_PATH", "data/katastr.db"),
 	}
 }
 
@@ -30,3 +131,71 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		slog.Warn("invalid integer in env, using default", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		slog.Warn("invalid number in env, using default", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return f
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		slog.Warn("invalid duration in env, using default", "key", key, "value", v, "default", fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvList parses a comma-separated list, skipping empty items.
+func getEnvList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// getEnvInts parses a comma-separated list of integers.
+func getEnvInts(key string, fallback []int) []int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	var out []int
+	for _, part := range strings.Split(v, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			slog.Warn("invalid integer list in env, using default", "key", key, "value", v)
+			return fallback
+		}
+		out = append(out, n)
+	}
+	return out
+}
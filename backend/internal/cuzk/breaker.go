@@ -0,0 +1,195 @@
+package cuzk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states.
+const (
+	BreakerClosed   = "closed"    // calls go through, failures are counted
+	BreakerOpen     = "open"      // calls fail fast with *CircuitOpenError
+	BreakerHalfOpen = "half-open" // one probe call decides between closed and open
+)
+
+// CircuitOpenError is returned without contacting CUZK while the breaker is open.
+type CircuitOpenError struct {
+	RetryAt time.Time // when the breaker lets the next probe through
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("cuzk: circuit breaker open, retry at %s", e.RetryAt.Format(time.RFC3339))
+}
+
+// IsCircuitOpen reports whether err was caused by an open circuit breaker.
+func IsCircuitOpen(err error) bool {
+	var openErr *CircuitOpenError
+	return errors.As(err, &openErr)
+}
+
+// breaker trips after threshold consecutive failed calls, rejects calls for
+// openTimeout, then lets a single probe through (half-open) to decide
+// whether CUZK is back.
+type breaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int // consecutive failures while closed
+	openedAt time.Time
+	probing  bool // a half-open probe is in flight
+	trips    int64
+}
+
+func newBreaker(threshold int, openTimeout time.Duration) *breaker {
+	return &breaker{threshold: threshold, openTimeout: openTimeout, state: BreakerClosed}
+}
+
+// Allow reports whether a call may proceed. When it returns nil the caller
+// must report the outcome with Record.
+func (b *breaker) Allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		retryAt := b.openedAt.Add(b.openTimeout)
+		if time.Now().Before(retryAt) {
+			return &CircuitOpenError{RetryAt: retryAt}
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return &CircuitOpenError{RetryAt: time.Now().Add(time.Second)}
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed call. A call that failed before
+// it reached CUZK is released as by Abandon: it neither resets the failure
+// count nor decides a half-open probe.
+func (b *breaker) Record(err error) {
+	if b == nil {
+		return
+	}
+	if !reachedUpstream(err) {
+		b.Abandon()
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := isOutage(err)
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		if failed {
+			b.trip()
+		} else {
+			b.state = BreakerClosed
+			b.failures = 0
+		}
+	case BreakerClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.trip()
+		}
+	}
+}
+
+// Abandon releases an allowed call whose caller gave up before CUZK answered;
+// the outcome says nothing about the upstream.
+func (b *breaker) Abandon() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probing = false
+	}
+}
+
+// isOpen reports whether calls are currently being rejected.
+func (b *breaker) isOpen() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == BreakerOpen
+}
+
+// trip opens the breaker. Must be called with b.mu held.
+func (b *breaker) trip() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.failures = 0
+	b.trips++
+}
+
+// reachedUpstream reports whether a call got an HTTP response from CUZK or
+// failed trying to. Errors raised before any request went out, such as
+// ErrQuotaExhausted, the rate limiter giving up or a missing fixture, say
+// nothing about CUZK.
+func reachedUpstream(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, ErrNoFixture) || errors.Is(err, errRetryAfterExceedsDeadline) {
+		return false
+	}
+	_, ok := AsAPIError(err)
+	return ok
+}
+
+// isOutage reports whether err means CUZK itself is failing: no response,
+// a timeout or a 5xx. Client errors, 404s and 429s do not count.
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, ErrNoFixture) {
+		return false
+	}
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	return apiErr.StatusCode == 0 || apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+// BreakerStats is a snapshot of the circuit breaker.
+type BreakerStats struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	Trips               int64      `json:"trips"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+}
+
+func (b *breaker) Stats() BreakerStats {
+	if b == nil {
+		return BreakerStats{State: "disabled"}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := BreakerStats{State: b.state, ConsecutiveFailures: b.failures, Trips: b.trips}
+	if b.state != BreakerClosed {
+		t := b.openedAt
+		s.OpenedAt = &t
+	}
+	return s
+}
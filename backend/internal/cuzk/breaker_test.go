@@ -0,0 +1,81 @@
+package cuzk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerTripsAndRecovers(t *testing.T) {
+	b := newBreaker(2, 20*time.Millisecond)
+	outage := &APIError{StatusCode: http.StatusServiceUnavailable}
+
+	for range 2 {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("closed breaker rejected call: %v", err)
+		}
+		b.Record(outage)
+	}
+	if err := b.Allow(); !IsCircuitOpen(err) {
+		t.Fatalf("expected open circuit after 2 failures, got %v", err)
+	}
+
+	time.Sleep(25 * time.Millisecond)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("expected half-open probe to be allowed, got %v", err)
+	}
+	if err := b.Allow(); !IsCircuitOpen(err) {
+		t.Fatalf("expected second call during probe to be rejected, got %v", err)
+	}
+	b.Record(nil)
+
+	if s := b.Stats(); s.State != BreakerClosed || s.Trips != 1 {
+		t.Errorf("stats = %+v, want closed after 1 trip", s)
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	b := newBreaker(1, time.Minute)
+	for _, status := range []int{http.StatusNotFound, http.StatusTooManyRequests, http.StatusUnauthorized} {
+		b.Allow()
+		b.Record(&APIError{StatusCode: status})
+	}
+	if s := b.Stats(); s.State != BreakerClosed {
+		t.Errorf("state = %s, want closed", s.State)
+	}
+}
+
+func TestBreakerProbeWithExhaustedKeysDecidesNothing(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"id":1}`))
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL, "", WithRateLimit(0, 1), WithCircuitBreaker(1, time.Minute), WithAPIKeys([]string{"key"}, 1, nil))
+	ctx := context.Background()
+	if _, err := c.GetParcel(ctx, 1); err != nil {
+		t.Fatalf("GetParcel: %v", err)
+	}
+
+	// Open the breaker and let its timeout pass: the next call is the probe.
+	c.breaker.mu.Lock()
+	c.breaker.trip()
+	c.breaker.openedAt = time.Now().Add(-time.Hour)
+	c.breaker.mu.Unlock()
+
+	if _, err := c.GetParcel(ctx, 1); !errors.Is(err, ErrQuotaExhausted) {
+		t.Fatalf("expected ErrQuotaExhausted, got %v", err)
+	}
+	if s := c.BreakerStats(); s.State != BreakerHalfOpen || requests.Load() != 1 {
+		t.Fatalf("state = %s after %d requests, want half-open after 1", s.State, requests.Load())
+	}
+	if err := c.breaker.Allow(); err != nil {
+		t.Errorf("probe not released: %v", err)
+	}
+}
@@ -20,7 +20,8 @@ func (c *Client) GetBuilding(ctx context.Context, id int64) (*Building, error) {
 	path := fmt.Sprintf("/Stavby/%d", id)
 	var b Building
 	if err := c.get(ctx, path, &b); err != nil {
-		return nil, fmt.Errorf("get building: %w", err)
+		return nil, fromLocal(ctx, c, "building", fmt.Errorf("get building: %w", err), func(l LocalSource) (*Building, error) { return l.LocalBuilding(ctx, id) })
 	}
+	c.observe(ctx, ObjectBuilding, id, &b)
 	return &b, nil
 }
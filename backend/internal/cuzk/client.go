@@ -3,6 +3,7 @@ package cuzk
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,37 +15,136 @@ import (
 // Client is an HTTP client for the CUZK REST API.
 type Client struct {
 	baseURL    string
-	apiKey     string
+	keys       *keyPool
 	httpClient *http.Client
-	limiter    *rate.Limiter
+	limiter    *adaptiveLimiter
+	breaker    *breaker    // nil when disabled
+	observer   Observer    // nil when unset
+	local      LocalSource // nil when unset
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithRateLimit sets the sustained request rate (per second) and burst.
+// A rate <= 0 disables limiting.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(c *Client) {
+		r := rate.Limit(perSecond)
+		if perSecond <= 0 {
+			r = rate.Inf
+		}
+		c.limiter = newAdaptiveLimiter(r, max(burst, 1))
+	}
+}
+
+// WithAPIKeys rotates requests among several API keys, each allowed dailyQuota
+// requests per day (0 means unlimited). Usage is persisted in usage, if not nil,
+// so restarts keep counting. A key CUZK rejects with 401/403 is skipped until
+// the next day. Replaces the key passed to NewClient.
+func WithAPIKeys(keys []string, dailyQuota int, usage UsageStore) Option {
+	return func(c *Client) {
+		c.keys = newKeyPool(keys, dailyQuota, usage)
+	}
+}
+
+// WithCircuitBreaker opens the circuit after threshold consecutive failed calls
+// (network errors, timeouts, 5xx) and fails fast for openTimeout before probing
+// CUZK again. A threshold <= 0 disables the breaker.
+func WithCircuitBreaker(threshold int, openTimeout time.Duration) Option {
+	return func(c *Client) {
+		if threshold <= 0 {
+			c.breaker = nil
+			return
+		}
+		c.breaker = newBreaker(threshold, openTimeout)
+	}
+}
+
+// WithRecording writes every request/response pair to dir as a fixture.
+func WithRecording(dir string) Option {
+	return func(c *Client) {
+		next := c.httpClient.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.httpClient.Transport = newFixtureTransport(c.baseURL, dir, next)
+	}
+}
+
+// WithReplay serves responses from fixtures in dir without network access.
+// The rate limiter is disabled since no upstream is involved.
+func WithReplay(dir string) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = newFixtureTransport(c.baseURL, dir, nil)
+		c.limiter = newAdaptiveLimiter(rate.Inf, 1)
+	}
 }
 
 // NewClient creates a new CUZK API client.
 // apiKey can be empty for development (requests will fail with 401).
-func NewClient(baseURL, apiKey string) *Client {
-	return &Client{
+func NewClient(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
 		baseURL: baseURL,
-		apiKey:  apiKey,
+		keys:    newKeyPool([]string{apiKey}, 0, nil),
 		httpClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
-		limiter: rate.NewLimiter(rate.Every(time.Second), 1), // 1 req/s
+		limiter: newAdaptiveLimiter(rate.Every(time.Second), 1), // 1 req/s
+		breaker: newBreaker(5, 30*time.Second),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
+}
+
+// LimiterStats reports the current state of the upstream rate limiter.
+func (c *Client) LimiterStats() LimiterStats {
+	return c.limiter.Stats()
+}
+
+// KeyStats reports today's usage and remaining quota of every API key.
+func (c *Client) KeyStats(ctx context.Context) []KeyStats {
+	return c.keys.stats(ctx)
+}
+
+// BreakerStats reports the current state of the circuit breaker.
+func (c *Client) BreakerStats() BreakerStats {
+	return c.breaker.Stats()
 }
 
 const maxRetries = 3
 
-// do executes an HTTP request with retry logic and rate limiting.
+// do executes an HTTP request through the circuit breaker.
+// While the breaker is open it fails fast with *CircuitOpenError.
 func (c *Client) do(ctx context.Context, method, path string) ([]byte, error) {
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit: %w", err)
+	if err := c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	body, err := c.attempt(ctx, method, path)
+	if ctx.Err() != nil {
+		c.breaker.Abandon()
+	} else {
+		c.breaker.Record(err)
 	}
+	return body, err
+}
 
+// attempt executes an HTTP request with retry logic and rate limiting.
+// Every attempt waits on the limiter; 429 answers slow the limiter down
+// and pause it for Retry-After instead of the fixed backoff.
+// Retries stop early once the breaker has been opened by other calls.
+func (c *Client) attempt(ctx context.Context, method, path string) ([]byte, error) {
 	url := c.baseURL + path
-	var lastErr error
+	var lastErr *APIError
 
 	for attempt := range maxRetries {
-		if attempt > 0 {
+		if attempt > 0 && c.breaker.isOpen() {
+			break
+		}
+		// 429s wait in the limiter and rejected keys are swapped, no backoff needed.
+		if attempt > 0 && lastErr.StatusCode != http.StatusTooManyRequests && !IsUnauthorized(lastErr) {
 			backoff := time.Duration(1<<(attempt-1)) * time.Second
 			select {
 			case <-time.After(backoff):
@@ -53,42 +153,81 @@ func (c *Client) do(ctx context.Context, method, path string) ([]byte, error) {
 			}
 		}
 
+		if err := c.limiter.Wait(ctx); err != nil {
+			if errors.Is(err, errRetryAfterExceedsDeadline) {
+				if lastErr != nil {
+					return nil, lastErr
+				}
+				return nil, &APIError{StatusCode: http.StatusTooManyRequests, Path: path, Err: err}
+			}
+			return nil, fmt.Errorf("rate limit: %w", err)
+		}
+
+		key, err := c.keys.acquire(ctx)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, err
+		}
+
 		req, err := http.NewRequestWithContext(ctx, method, url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("create request: %w", err)
 		}
 
-		if c.apiKey != "" {
-			req.Header.Set("Api-Key", c.apiKey)
+		if key != nil {
+			req.Header.Set("Api-Key", key.value)
 		}
 		req.Header.Set("Accept", "application/json")
 
 		resp, err := c.httpClient.Do(req)
+		if errors.Is(err, ErrNoFixture) {
+			return nil, err
+		}
 		if err != nil {
-			lastErr = fmt.Errorf("attempt %d/%d: %w", attempt+1, maxRetries, err)
+			lastErr = &APIError{Path: path, Retries: attempt, Err: err}
 			continue
 		}
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			lastErr = fmt.Errorf("read body: %w", err)
+			lastErr = &APIError{StatusCode: resp.StatusCode, Path: path, Retries: attempt, Err: fmt.Errorf("read body: %w", err)}
 			continue
 		}
 
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter := parseRetryAfter(resp.Header, time.Now())
+			if retryAfter == 0 {
+				retryAfter = time.Duration(1<<attempt) * time.Second
+			}
+			c.limiter.Throttle(retryAfter)
+		}
 		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
-			lastErr = fmt.Errorf("HTTP %d (attempt %d/%d)", resp.StatusCode, attempt+1, maxRetries)
+			lastErr = newAPIError(path, resp.StatusCode, body)
+			lastErr.Retries = attempt
 			continue
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+			apiErr := newAPIError(path, resp.StatusCode, body)
+			apiErr.Retries = attempt
+			if key != nil && IsUnauthorized(apiErr) {
+				c.keys.reject(key)
+				if c.keys.usable() {
+					lastErr = apiErr
+					continue
+				}
+			}
+			return nil, apiErr
 		}
 
+		c.limiter.Success()
 		return body, nil
 	}
 
-	return nil, fmt.Errorf("max retries exceeded: %w", lastErr)
+	return nil, lastErr
 }
 
 // get performs a GET request and decodes the JSON response into target.
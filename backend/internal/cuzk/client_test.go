@@ -0,0 +1,270 @@
+package cuzk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/cuzkmock"
+)
+
+func TestGetParcel(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("test-key")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "test-key")
+	p, err := c.GetParcel(context.Background(), 729272101)
+	if err != nil {
+		t.Fatalf("GetParcel: %v", err)
+	}
+	if p.BaseNumber != 1520 || p.CadastralArea.Name != "Dejvice" {
+		t.Errorf("unexpected parcel: %+v", p)
+	}
+}
+
+func TestSearchParcels(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "")
+	resp, err := c.SearchParcels(context.Background(), 729272, cuzk.ParcelNumber{Base: 1521, Type: cuzk.NumberingLand})
+	if err != nil {
+		t.Fatalf("SearchParcels: %v", err)
+	}
+	if resp.Total != 2 || len(resp.Parcels) != 2 {
+		t.Errorf("expected 2 parcels (1521/1, 1521/2), got %d", len(resp.Parcels))
+	}
+
+	number, _ := cuzk.ParseParcelNumber("1521/2")
+	resp, err = c.SearchParcels(context.Background(), 729272, number)
+	if err != nil {
+		t.Fatalf("SearchParcels: %v", err)
+	}
+	if len(resp.Parcels) != 1 || resp.Parcels[0].ID != 729272103 {
+		t.Errorf("expected only 1521/2, got %+v", resp.Parcels)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	mock, ts := cuzkmock.NewTestServer("test-key")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "wrong-key")
+	_, err := c.GetParcel(context.Background(), 729272101)
+	if !cuzk.IsUnauthorized(err) {
+		t.Fatalf("expected unauthorized error, got %v", err)
+	}
+	if n := mock.Requests(); n != 1 {
+		t.Errorf("401 must not be retried, got %d requests", n)
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "")
+	_, err := c.GetParcel(context.Background(), 1)
+	if !cuzk.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	apiErr, ok := cuzk.AsAPIError(err)
+	if !ok || apiErr.Code != "NOT_FOUND" || apiErr.Path != "/Parcely/1" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestRetryOnServerError(t *testing.T) {
+	mock, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	mock.InjectFault(cuzkmock.Fault{Status: http.StatusServiceUnavailable, Count: 1})
+
+	c := cuzk.NewClient(ts.URL, "")
+	if _, err := c.GetBuilding(context.Background(), 729272501); err != nil {
+		t.Fatalf("expected success after retry, got %v", err)
+	}
+	if n := mock.Requests(); n != 2 {
+		t.Errorf("expected 2 requests (1 fault + 1 retry), got %d", n)
+	}
+}
+
+func TestRateLimitedHonorsRetryAfter(t *testing.T) {
+	mock, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	mock.InjectFault(cuzkmock.Fault{Status: http.StatusTooManyRequests, Count: 1, RetryAfter: 1})
+
+	c := cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(10, 1))
+	start := time.Now()
+	if _, err := c.GetBuilding(context.Background(), 729272501); err != nil {
+		t.Fatalf("expected success after Retry-After, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+
+	stats := c.LimiterStats()
+	if stats.Throttled != 1 {
+		t.Errorf("Throttled = %d, want 1", stats.Throttled)
+	}
+	// Halved to 5 req/s by the 429, then one recovery step (+1) on success.
+	if stats.Rate != 6 || stats.BaseRate != 10 {
+		t.Errorf("Rate = %v, BaseRate = %v, want 6 and 10", stats.Rate, stats.BaseRate)
+	}
+}
+
+func TestAPIKeyRotationAndQuota(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("test-key")
+	defer ts.Close()
+
+	usage := cache.NewMemoryCache(100, 0)
+	c := cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1), cuzk.WithAPIKeys([]string{"revoked-key", "test-key"}, 2, usage))
+	ctx := context.Background()
+
+	// The revoked key gets a 401 and the call is retried with the next key.
+	for range 2 {
+		if _, err := c.GetParcel(ctx, 729272101); err != nil {
+			t.Fatalf("expected success with the valid key, got %v", err)
+		}
+	}
+	if _, err := c.GetParcel(ctx, 729272101); !errors.Is(err, cuzk.ErrQuotaExhausted) {
+		t.Fatalf("expected ErrQuotaExhausted, got %v", err)
+	}
+
+	stats := c.KeyStats(ctx)
+	if len(stats) != 2 || stats[0].State != cuzk.KeyUnauthorized || stats[1].State != cuzk.KeyExhausted || stats[1].Used != 2 {
+		t.Errorf("unexpected key stats %+v", stats)
+	}
+	if keys, _ := usage.Keys(ctx, "quota:cuzk:"); len(keys) != 2 {
+		t.Errorf("expected usage of both keys persisted, got %v", keys)
+	}
+}
+
+func TestGetOwnershipSheet(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1))
+	lv, err := c.GetOwnershipSheet(context.Background(), 729272, 1187)
+	if err != nil {
+		t.Fatalf("GetOwnershipSheet: %v", err)
+	}
+	if len(lv.Owners) != 2 || lv.Owners[0].Share == nil || lv.Owners[0].Share.Denominator != 2 {
+		t.Errorf("expected two owners with 1/2 shares, got %+v", lv.Owners)
+	}
+	if len(lv.Parcels) != 3 || len(lv.Buildings) != 1 || len(lv.Units) != 1 || len(lv.Encumbrances) != 2 {
+		t.Errorf("unexpected sheet contents: %d parcels, %d buildings, %d units, %d encumbrances",
+			len(lv.Parcels), len(lv.Buildings), len(lv.Units), len(lv.Encumbrances))
+	}
+
+	if _, err := c.GetOwnershipSheet(context.Background(), 730122, 1187); !cuzk.IsNotFound(err) {
+		t.Errorf("expected 404 for an LV number from another area, got %v", err)
+	}
+}
+
+func TestGetRights(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1))
+	resp, err := c.GetUnitRights(context.Background(), 729272801)
+	if err != nil {
+		t.Fatalf("GetUnitRights: %v", err)
+	}
+	if len(resp.Rights) != 2 {
+		t.Fatalf("expected 2 rights, got %+v", resp.Rights)
+	}
+	lien := resp.Rights[0]
+	if lien.Category != cuzk.RightLien || lien.Beneficiary == nil || lien.Obligor == nil || lien.LegalBasis == nil || len(lien.ProceedingIDs) != 1 {
+		t.Errorf("unexpected lien %+v", lien)
+	}
+	if resp.Rights[1].Category != cuzk.RightOther {
+		t.Errorf("expected zákaz zcizení to be categorized as other, got %s", resp.Rights[1].Category)
+	}
+
+	resp, err = c.GetParcelRights(context.Background(), 729272102)
+	if err != nil || resp.Rights == nil || len(resp.Rights) != 0 {
+		t.Errorf("expected empty rights list for unencumbered parcel, got %+v, %v", resp, err)
+	}
+}
+
+func TestSearchProceedings(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	c := cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1))
+	d, _ := cuzk.ParseProceedingDesignation("V-1234/2025-101")
+	resp, err := c.SearchProceedings(context.Background(), d)
+	if err != nil {
+		t.Fatalf("SearchProceedings: %v", err)
+	}
+	if len(resp.Proceedings) != 1 || resp.Proceedings[0].ID != 9101001 {
+		t.Errorf("expected proceeding 9101001, got %+v", resp.Proceedings)
+	}
+
+	resp, err = c.SearchProceedings(context.Background(), cuzk.ProceedingDesignation{Year: 2026, Office: "101"})
+	if err != nil || resp.Total != 2 {
+		t.Errorf("expected 2 proceedings in 2026, got %+v, %v", resp, err)
+	}
+
+	resp, err = c.GetParcelProceedings(context.Background(), 729272101)
+	if err != nil || resp.Total != 2 {
+		t.Errorf("expected 2 proceedings on parcel st. 1520, got %+v, %v", resp, err)
+	}
+}
+
+// localParcels serves one parcel; other LocalSource methods are not used.
+type localParcels struct {
+	cuzk.LocalSource
+	parcel cuzk.Parcel
+	calls  int
+}
+
+func (l *localParcels) LocalParcel(ctx context.Context, id int64) (*cuzk.Parcel, error) {
+	l.calls++
+	if id != l.parcel.ID {
+		return nil, nil
+	}
+	p := l.parcel
+	return &p, nil
+}
+
+type countingObserver struct{ n int }
+
+func (o *countingObserver) Observe(context.Context, string, int64, any) { o.n++ }
+
+func TestLocalSourceIsFallbackOnly(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("test-key")
+	defer ts.Close()
+
+	local := &localParcels{parcel: cuzk.Parcel{ID: 729272101, BaseNumber: 9999}}
+	var obs countingObserver
+	ctx := context.Background()
+
+	// CUZK answers: the local copy is not consulted.
+	c := cuzk.NewClient(ts.URL, "test-key", cuzk.WithRateLimit(0, 1), cuzk.WithLocalSource(local), cuzk.WithObserver(&obs))
+	if p, err := c.GetParcel(ctx, 729272101); err != nil || p.BaseNumber != 1520 {
+		t.Fatalf("expected the CUZK parcel, got %+v, %v", p, err)
+	}
+	if _, err := c.GetParcel(ctx, 1); !cuzk.IsNotFound(err) {
+		t.Errorf("expected a CUZK 404 to stand, got %v", err)
+	}
+	if local.calls != 0 || obs.n != 1 {
+		t.Errorf("local calls = %d, observed = %d, want 0 and 1", local.calls, obs.n)
+	}
+
+	// Without an API key CUZK refuses; the local copy comes with the error, unobserved.
+	c = cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1), cuzk.WithLocalSource(local), cuzk.WithObserver(&obs))
+	_, err := c.GetParcel(ctx, 729272101)
+	if p, ok := cuzk.AsLocalData(err); !ok || p.(*cuzk.Parcel).BaseNumber != 9999 || !cuzk.IsUnauthorized(err) {
+		t.Fatalf("expected the local parcel with the CUZK error, got %v", err)
+	}
+	if obs.n != 1 {
+		t.Errorf("local read was reported to the observer")
+	}
+}
@@ -0,0 +1,118 @@
+package cuzk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// APIError describes a failed CUZK API call.
+// StatusCode is 0 when no HTTP response was received (network error, timeout).
+type APIError struct {
+	StatusCode int    // HTTP status returned by CUZK
+	Code       string // CUZK error code from the response body ("kod"), if any
+	Message    string // CUZK error message ("zprava") or the raw body
+	Path       string // request path relative to the base URL
+	Retries    int    // retries performed before giving up
+	Err        error  // underlying transport error, if any
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "cuzk %s: ", e.Path)
+	if e.StatusCode == 0 {
+		fmt.Fprintf(&b, "request failed")
+	} else {
+		fmt.Fprintf(&b, "HTTP %d", e.StatusCode)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " %s", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	if e.Retries > 0 {
+		fmt.Fprintf(&b, " (after %d retries)", e.Retries)
+	}
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the call failed because the upstream did not answer in time.
+func (e *APIError) Timeout() bool {
+	if e.StatusCode == http.StatusGatewayTimeout {
+		return true
+	}
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// newAPIError builds an APIError from a non-success response body.
+func newAPIError(path string, status int, body []byte) *APIError {
+	e := &APIError{StatusCode: status, Path: path}
+
+	var parsed struct {
+		Code    string `json:"kod"`
+		Message string `json:"zprava"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && (parsed.Code != "" || parsed.Message != "") {
+		e.Code = parsed.Code
+		e.Message = parsed.Message
+		return e
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > 512 {
+		msg = msg[:512]
+	}
+	e.Message = msg
+	return e
+}
+
+// AsAPIError returns the APIError wrapped in err, if any.
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound reports whether CUZK answered 404 for the requested object.
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether CUZK rejected the API key (401 or 403).
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized) || hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether CUZK answered 429 Too Many Requests.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsTimeout reports whether the upstream call timed out.
+func IsTimeout(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.Timeout()
+}
+
+func hasStatus(err error, status int) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.StatusCode == status
+}
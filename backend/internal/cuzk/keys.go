@@ -0,0 +1,266 @@
+package cuzk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+	_ "time/tzdata" // quota days follow Prague time even in minimal containers
+)
+
+// ErrQuotaExhausted is returned when every configured API key has used up its
+// daily quota or has been rejected by CUZK.
+var ErrQuotaExhausted = errors.New("cuzk: no API key with remaining quota")
+
+// usageTTL keeps a day's counter around long enough to outlive the day in any time zone.
+const usageTTL = 48 * time.Hour
+
+// quotaLocation is where CUZK quota days start and end.
+var quotaLocation = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// UsageStore persists per-key request counters across restarts; cache.Store satisfies it.
+// Get must return an error when the key does not exist.
+type UsageStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+}
+
+// Key states reported in KeyStats.
+const (
+	KeyActive       = "active"
+	KeyExhausted    = "exhausted"    // daily quota used up
+	KeyUnauthorized = "unauthorized" // CUZK answered 401/403, retried the next day
+)
+
+// usageTimeout bounds a single UsageStore call, independent of the request.
+const usageTimeout = time.Second
+
+type apiKey struct {
+	value string
+	id    string // stable, non-secret identifier used in storage keys and stats
+
+	day          string // quota day the counters below belong to
+	used         int
+	loaded       string // day whose persisted count has been merged into used
+	unauthorized bool
+	writing      bool // a caller is persisting used
+	dirty        bool // used changed while it was being persisted
+}
+
+// keyPool rotates requests among API keys and counts their daily usage.
+// p.mu guards the counters only; UsageStore I/O always happens without it.
+type keyPool struct {
+	quota int // requests per key per day; 0 means unlimited
+	usage UsageStore
+
+	mu   sync.Mutex
+	keys []*apiKey
+	next int
+}
+
+func newKeyPool(keys []string, dailyQuota int, usage UsageStore) *keyPool {
+	p := &keyPool{quota: dailyQuota, usage: usage}
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		sum := sha256.Sum256([]byte(k))
+		p.keys = append(p.keys, &apiKey{value: k, id: hex.EncodeToString(sum[:4])})
+	}
+	return p
+}
+
+// acquire picks the next usable key round-robin and counts one request against it.
+// It returns nil without error when no keys are configured at all.
+func (p *keyPool) acquire(ctx context.Context) (*apiKey, error) {
+	if len(p.keys) == 0 {
+		return nil, nil
+	}
+	day := quotaDay(time.Now())
+	p.load(ctx, day)
+
+	k, write := p.pick(day)
+	if k == nil {
+		return nil, ErrQuotaExhausted
+	}
+	if write {
+		p.persist(ctx, k)
+	}
+	return k, nil
+}
+
+// pick counts a request against the next usable key. write reports whether
+// the caller has to persist the counter; otherwise a concurrent caller that
+// is already persisting it writes the new value too.
+func (p *keyPool) pick(day string) (k *apiKey, write bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for range len(p.keys) {
+		k := p.keys[p.next]
+		p.next = (p.next + 1) % len(p.keys)
+
+		p.rollover(k, day)
+		if k.unauthorized || (p.quota > 0 && k.used >= p.quota) {
+			continue
+		}
+		k.used++
+		if k.writing {
+			k.dirty = true
+			return k, false
+		}
+		k.writing = true
+		return k, true
+	}
+	return nil, false
+}
+
+// reject stops using k for the rest of the day after CUZK refused it.
+func (p *keyPool) reject(k *apiKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !k.unauthorized {
+		slog.Warn("CUZK rejected API key, disabling it until tomorrow", "key", k.id)
+	}
+	k.unauthorized = true
+}
+
+// usable reports whether any key can still be used today.
+func (p *keyPool) usable() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, k := range p.keys {
+		if !k.unauthorized && (p.quota == 0 || k.used < p.quota) {
+			return true
+		}
+	}
+	return false
+}
+
+// rollover resets k's counters when a new quota day starts. Must be called
+// with p.mu held.
+func (p *keyPool) rollover(k *apiKey, day string) {
+	if k.day != day {
+		k.day, k.used, k.unauthorized = day, 0, false
+	}
+}
+
+// load merges the persisted counts of keys not yet loaded for day, once per
+// day. Concurrent callers may both read a count; merging takes the maximum.
+func (p *keyPool) load(ctx context.Context, day string) {
+	if p.usage == nil {
+		return
+	}
+	p.mu.Lock()
+	var pending []*apiKey
+	for _, k := range p.keys {
+		if k.loaded != day {
+			pending = append(pending, k)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, k := range pending {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageTimeout)
+		v, err := p.usage.Get(ctx, usageKey(k.id, day))
+		cancel()
+		n, convErr := strconv.Atoi(v)
+
+		p.mu.Lock()
+		p.rollover(k, day)
+		if err == nil && convErr == nil {
+			k.used = max(k.used, n)
+		}
+		k.loaded = day
+		p.mu.Unlock()
+	}
+}
+
+// persist writes k's counter to the UsageStore. Only the caller that set
+// k.writing calls it, so writes of one key never overtake each other; a
+// change made meanwhile is written once more before handing back.
+func (p *keyPool) persist(ctx context.Context, k *apiKey) {
+	defer func() {
+		p.mu.Lock()
+		k.writing = false
+		p.mu.Unlock()
+	}()
+	if p.usage == nil {
+		return
+	}
+	for range 2 {
+		p.mu.Lock()
+		day, used := k.day, k.used
+		k.dirty = false
+		p.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), usageTimeout)
+		err := p.usage.Set(ctx, usageKey(k.id, day), strconv.Itoa(used), usageTTL)
+		cancel()
+		if err != nil {
+			slog.Warn("persist CUZK key usage", "key", k.id, "error", err)
+		}
+
+		p.mu.Lock()
+		dirty := k.dirty
+		p.mu.Unlock()
+		if !dirty {
+			return
+		}
+	}
+}
+
+// KeyStats reports the usage of one API key for the current quota day.
+type KeyStats struct {
+	ID         string `json:"id"` // hash prefix, safe to show
+	State      string `json:"state"`
+	Day        string `json:"day"`
+	Used       int    `json:"used"`
+	DailyQuota int    `json:"dailyQuota,omitempty"`
+	Remaining  *int   `json:"remaining,omitempty"` // nil when unlimited
+}
+
+func (p *keyPool) stats(ctx context.Context) []KeyStats {
+	day := quotaDay(time.Now())
+	p.load(ctx, day)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		p.rollover(k, day)
+		s := KeyStats{ID: k.id, State: KeyActive, Day: day, Used: k.used}
+		if p.quota > 0 {
+			remaining := max(p.quota-k.used, 0)
+			s.DailyQuota = p.quota
+			s.Remaining = &remaining
+			if remaining == 0 {
+				s.State = KeyExhausted
+			}
+		}
+		if k.unauthorized {
+			s.State = KeyUnauthorized
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func quotaDay(t time.Time) string {
+	return t.In(quotaLocation).Format(time.DateOnly)
+}
+
+func usageKey(id, day string) string {
+	return "quota:cuzk:" + id + ":" + day
+}
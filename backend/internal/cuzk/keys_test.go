@@ -0,0 +1,61 @@
+package cuzk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingUsage holds every Set until release is closed.
+type blockingUsage struct {
+	mu      sync.Mutex
+	release chan struct{}
+	values  map[string]string
+}
+
+func (u *blockingUsage) Get(ctx context.Context, key string) (string, error) {
+	return "", errors.New("miss")
+}
+
+func (u *blockingUsage) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	<-u.release
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.values[key] = value
+	return nil
+}
+
+func TestKeyPoolPersistsOutsideLock(t *testing.T) {
+	usage := &blockingUsage{release: make(chan struct{}), values: map[string]string{}}
+	p := newKeyPool([]string{"secret-key"}, 0, usage)
+	ctx := context.Background()
+
+	first := make(chan struct{})
+	go func() {
+		p.acquire(ctx)
+		close(first)
+	}()
+	time.Sleep(20 * time.Millisecond) // let the first call block in Set
+
+	done := make(chan struct{})
+	go func() {
+		for range 3 {
+			p.acquire(ctx)
+		}
+		p.stats(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("acquire blocked behind a slow usage store")
+	}
+
+	close(usage.release)
+	<-first
+	if got := usage.values[usageKey(p.keys[0].id, quotaDay(time.Now()))]; got != "4" {
+		t.Errorf("persisted usage = %q, want 4", got)
+	}
+}
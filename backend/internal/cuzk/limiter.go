@@ -0,0 +1,163 @@
+package cuzk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minRateFraction is the lowest the limiter backs off to, relative to the configured rate.
+	minRateFraction = 1.0 / 16
+	// recoveryStep is how much of the configured rate each success wins back.
+	recoveryStep = 0.1
+	// maxRetryAfter caps how long a single Retry-After pauses all requests.
+	maxRetryAfter = 5 * time.Minute
+)
+
+// errRetryAfterExceedsDeadline is returned by Wait when a Retry-After pause outlasts the caller's deadline.
+var errRetryAfterExceedsDeadline = errors.New("cuzk: Retry-After pause exceeds request deadline")
+
+// adaptiveLimiter is a token bucket that halves its rate on every 429,
+// pauses until Retry-After has passed, and recovers additively on success.
+// Callers reach the bucket through a priority queue.
+type adaptiveLimiter struct {
+	limiter  *rate.Limiter
+	baseRate rate.Limit
+	queue    priorityQueue
+
+	mu           sync.Mutex
+	pausedUntil  time.Time
+	throttled    int64
+	lastThrottle time.Time
+}
+
+func newAdaptiveLimiter(r rate.Limit, burst int) *adaptiveLimiter {
+	return &adaptiveLimiter{
+		limiter:  rate.NewLimiter(r, burst),
+		baseRate: r,
+	}
+}
+
+// Wait blocks until a request may be sent: its turn in the priority queue
+// (see WithPriority), past any Retry-After pause and with a token available.
+// It fails fast if ctx would expire first.
+func (l *adaptiveLimiter) Wait(ctx context.Context) error {
+	if err := l.queue.acquire(ctx, PriorityFrom(ctx)); err != nil {
+		return err
+	}
+	defer l.queue.release()
+
+	l.mu.Lock()
+	pause := time.Until(l.pausedUntil)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < pause {
+			return errRetryAfterExceedsDeadline
+		}
+		t := time.NewTimer(pause)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// Throttle records a 429: the rate is halved (down to minRateFraction of the
+// configured rate) and all requests pause for retryAfter, if given.
+func (l *adaptiveLimiter) Throttle(retryAfter time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.throttled++
+	l.lastThrottle = now
+	if retryAfter > 0 {
+		l.pausedUntil = now.Add(min(retryAfter, maxRetryAfter))
+	}
+
+	if l.baseRate == rate.Inf {
+		return
+	}
+	l.limiter.SetLimitAt(now, max(l.limiter.Limit()/2, l.baseRate*minRateFraction))
+}
+
+// Success wins back recoveryStep of the configured rate after a backoff.
+func (l *adaptiveLimiter) Success() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	cur := l.limiter.Limit()
+	if cur >= l.baseRate {
+		return
+	}
+	l.limiter.SetLimit(min(cur+l.baseRate*recoveryStep, l.baseRate))
+}
+
+// LimiterStats is a snapshot of the upstream rate limiter.
+type LimiterStats struct {
+	Rate         float64        `json:"rate"`     // current requests per second
+	BaseRate     float64        `json:"baseRate"` // configured requests per second
+	Burst        int            `json:"burst"`
+	Tokens       float64        `json:"tokens"` // tokens available now
+	Throttled    int64          `json:"throttled"`
+	LastThrottle *time.Time     `json:"lastThrottle,omitempty"`
+	PausedUntil  *time.Time     `json:"pausedUntil,omitempty"`
+	Queued       map[string]int `json:"queued"` // callers waiting per priority
+}
+
+func (l *adaptiveLimiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := LimiterStats{
+		Rate:      limitValue(l.limiter.Limit()),
+		BaseRate:  limitValue(l.baseRate),
+		Burst:     l.limiter.Burst(),
+		Tokens:    l.limiter.Tokens(),
+		Throttled: l.throttled,
+		Queued:    l.queue.waiting(),
+	}
+	if !l.lastThrottle.IsZero() {
+		t := l.lastThrottle
+		s.LastThrottle = &t
+	}
+	if time.Now().Before(l.pausedUntil) {
+		t := l.pausedUntil
+		s.PausedUntil = &t
+	}
+	return s
+}
+
+// limitValue converts a rate.Limit to a JSON-safe number; 0 stands for unlimited.
+func limitValue(r rate.Limit) float64 {
+	if r == rate.Inf {
+		return 0
+	}
+	return float64(r)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+// It returns 0 when the header is missing or invalid.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+package cuzk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// LocalSource serves objects from a local copy of the cadastre, such as an
+// imported VFK extract, when CUZK cannot answer. Methods return nil and no
+// error for objects the source does not hold.
+type LocalSource interface {
+	LocalParcel(ctx context.Context, id int64) (*Parcel, error)
+	LocalParcels(ctx context.Context, areaCode int, number ParcelNumber) (*ParcelSearchResponse, error)
+	LocalNeighbors(ctx context.Context, id int64) (*NeighborParcelsResponse, error)
+	LocalBuilding(ctx context.Context, id int64) (*Building, error)
+	LocalUnit(ctx context.Context, id int64) (*Unit, error)
+	LocalOwnershipSheet(ctx context.Context, areaCode, number int) (*OwnershipSheet, error)
+}
+
+// WithLocalSource falls back to src for parcel, building, unit and ownership
+// sheet requests that CUZK fails, e.g. without an API key, out of quota or
+// while it is down. The local copy comes back in a *LocalDataError rather
+// than as a result: it is a snapshot of the extract's date, so callers must
+// choose to serve it and it is never reported to the Observer.
+func WithLocalSource(src LocalSource) Option {
+	return func(c *Client) {
+		c.local = src
+	}
+}
+
+// LocalDataError is returned when CUZK failed but the local source holds the
+// object. Unwrap returns the CUZK error, so callers that do not look for
+// local data see the failure as it is.
+type LocalDataError struct {
+	Object any // the local copy, e.g. *Parcel
+	Err    error
+}
+
+func (e *LocalDataError) Error() string {
+	return e.Err.Error() + " (local data available)"
+}
+
+func (e *LocalDataError) Unwrap() error {
+	return e.Err
+}
+
+// AsLocalData returns the local copy carried by err, if any.
+func AsLocalData(err error) (any, bool) {
+	var localErr *LocalDataError
+	if errors.As(err, &localErr) {
+		return localErr.Object, true
+	}
+	return nil, false
+}
+
+// fromLocal is called after CUZK failed with err. It returns a
+// *LocalDataError with the object fetch finds in the local source, or err
+// when there is none. A 404 from CUZK and a cancelled request are final and
+// never fall back.
+func fromLocal[T any](ctx context.Context, c *Client, what string, err error, fetch func(LocalSource) (*T, error)) error {
+	if c.local == nil || IsNotFound(err) || ctx.Err() != nil {
+		return err
+	}
+	v, lerr := fetch(c.local)
+	if lerr != nil {
+		slog.WarnContext(ctx, "local data unavailable", "object", what, "error", lerr)
+		return err
+	}
+	if v == nil {
+		return err
+	}
+	return &LocalDataError{Object: v, Err: err}
+}
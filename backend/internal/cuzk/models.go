@@ -40,15 +40,20 @@ type Building struct {
 	CadastralArea CadastralArea `json:"katastralniUzemi"`
 	UsageType     *string       `json:"zpusobVyuziti,omitempty"`
 	ParcelNumber  *string       `json:"parcelneCislo,omitempty"`
+	// OwnershipSheet is the LV number (číslo listu vlastnictví) within CadastralArea.
+	OwnershipSheet *string `json:"cisloLV,omitempty"`
 }
 
 // Unit represents a property unit such as an apartment (jednotka).
 type Unit struct {
-	ID               int64  `json:"id"`
-	UnitNumber       string `json:"cisloJednotky"`
-	UnitType         string `json:"typJednotky"`
-	CommonPartsShare string `json:"podilNaSpolecnychCastech"`
-	BuildingID       *int64 `json:"stavbaId,omitempty"`
+	ID               int64          `json:"id"`
+	UnitNumber       string         `json:"cisloJednotky"`
+	UnitType         string         `json:"typJednotky"`
+	CommonPartsShare string         `json:"podilNaSpolecnychCastech"`
+	BuildingID       *int64         `json:"stavbaId,omitempty"`
+	CadastralArea    *CadastralArea `json:"katastralniUzemi,omitempty"`
+	// OwnershipSheet is the LV number (číslo listu vlastnictví) within CadastralArea.
+	OwnershipSheet *string `json:"cisloLV,omitempty"`
 }
 
 // Proceeding represents a cadastral proceeding (řízení).
@@ -60,6 +65,108 @@ type Proceeding struct {
 	Status         string     `json:"stavRizeni"`
 	Type           string     `json:"typRizeni"`
 	FilingDate     *time.Time `json:"datumPodani,omitempty"`
+
+	// Filled in on request (see Client.GetProceedingOperations etc.); nil when not loaded.
+	Operations   []ProceedingOperation   `json:"operace,omitzero"`
+	Participants []ProceedingParticipant `json:"ucastnici,omitzero"`
+	Objects      []ProceedingObject      `json:"predmety,omitzero"`
+	Documents    []ProceedingDocument    `json:"listiny,omitzero"`
+}
+
+// ProceedingOperation is one step in the proceeding timeline, e.g. filing,
+// placing the seal (plomba), the decision or completing the entry.
+type ProceedingOperation struct {
+	Type        string    `json:"typ"` // e.g. "přijetí", "plomba", "rozhodnutí", "zápis"
+	Description string    `json:"popis"`
+	Date        time.Time `json:"datum"`
+}
+
+// ProceedingParticipant is a party to a proceeding.
+type ProceedingParticipant struct {
+	Name    string  `json:"nazev"`
+	Role    string  `json:"role"` // e.g. "navrhovatel", "účastník", "zástavní věřitel"
+	Address *string `json:"adresa,omitempty"`
+}
+
+// ProceedingObject is a parcel, building or unit affected by a proceeding.
+type ProceedingObject struct {
+	Kind          string         `json:"typ"` // "parcela", "stavba" or "jednotka"
+	ID            int64          `json:"id"`
+	Label         string         `json:"oznaceni"` // e.g. "st. 1520"
+	CadastralArea *CadastralArea `json:"katastralniUzemi,omitempty"`
+}
+
+// ProceedingDocument is a deed or decision filed in a proceeding (listina).
+type ProceedingDocument struct {
+	Type        string     `json:"typ"` // e.g. "smlouva kupní", "rozhodnutí"
+	Description string     `json:"popis"`
+	Date        *time.Time `json:"datum,omitempty"`
+}
+
+// OwnershipSheet represents a title deed (list vlastnictví, LV): the owners
+// of a set of parcels, buildings and units in one cadastral area and the
+// encumbrances registered against them.
+type OwnershipSheet struct {
+	Number        string        `json:"cisloLV"`
+	CadastralArea CadastralArea `json:"katastralniUzemi"`
+	Owners        []Owner       `json:"vlastnici"`
+	Parcels       []Parcel      `json:"parcely"`
+	Buildings     []Building    `json:"stavby"`
+	Units         []Unit        `json:"jednotky"`
+	Encumbrances  []Encumbrance `json:"omezeni"`
+}
+
+// Owner is a holder of a right recorded on an ownership sheet.
+type Owner struct {
+	Name    string  `json:"nazev"`
+	Type    string  `json:"typ"`         // e.g. "fyzická osoba", "právnická osoba", "SJM"
+	Right   string  `json:"pravniVztah"` // e.g. "vlastnické právo", "právo hospodařit s majetkem státu"
+	Address *string `json:"adresa,omitempty"`
+	Share   *Share  `json:"podil,omitempty"` // nil means the whole
+}
+
+// Share is a fractional co-ownership share (spoluvlastnický podíl).
+type Share struct {
+	Numerator   int `json:"citatel"`
+	Denominator int `json:"jmenovatel"`
+}
+
+// Encumbrance is a restriction of ownership (omezení vlastnického práva)
+// such as an easement (věcné břemeno) or a lien (zástavní právo).
+type Encumbrance struct {
+	Type        string  `json:"typ"`
+	Description string  `json:"popis"`
+	Beneficiary *string `json:"opravneny,omitempty"`
+	Document    *string `json:"listina,omitempty"` // proceeding that recorded it, e.g. "V-1234/2025-101"
+}
+
+// RightCategory groups rights by what they mean for a buyer.
+type RightCategory string
+
+const (
+	RightEasement   RightCategory = "easement"   // věcné břemeno, služebnost
+	RightLien       RightCategory = "lien"       // zástavní právo
+	RightPreemption RightCategory = "preemption" // předkupní právo
+	RightOther      RightCategory = "other"      // e.g. zákaz zcizení, exekuce, poznámky
+)
+
+// Right is a right or restriction recorded against a parcel, building or unit
+// (právní vztah), e.g. a mortgage, an easement or a pre-emption right.
+type Right struct {
+	Type          string        `json:"typ"`       // as recorded, e.g. "zástavní právo smluvní"
+	Category      RightCategory `json:"kategorie"` // derived from Type by the client
+	Description   string        `json:"popis"`
+	Beneficiary   *string       `json:"opravneny,omitempty"`    // who the right benefits
+	Obligor       *string       `json:"povinny,omitempty"`      // who it burdens
+	LegalBasis    *string       `json:"pravniZaklad,omitempty"` // e.g. the contract it was created by
+	ProceedingIDs []int64       `json:"rizeniIds"`              // resolvable via GetProceeding
+	ValidFrom     *time.Time    `json:"platnostOd,omitempty"`
+}
+
+// RightsResponse lists the rights recorded against one object.
+type RightsResponse struct {
+	ObjectID int64   `json:"objektId"`
+	Rights   []Right `json:"pravniVztahy"`
 }
 
 // ParcelSearchResponse wraps a list of parcels from a search query.
@@ -68,18 +175,44 @@ type ParcelSearchResponse struct {
 	Total   int      `json:"total"`
 }
 
+// IsEmpty reports whether the search matched no parcels.
+func (r *ParcelSearchResponse) IsEmpty() bool {
+	return len(r.Parcels) == 0
+}
+
 // BuildingSearchResponse wraps a list of buildings from a search query.
 type BuildingSearchResponse struct {
 	Buildings []Building `json:"stavby"`
 	Total     int        `json:"total"`
 }
 
+// IsEmpty reports whether the search matched no buildings.
+func (r *BuildingSearchResponse) IsEmpty() bool {
+	return len(r.Buildings) == 0
+}
+
 // UnitSearchResponse wraps a list of units from a search query.
 type UnitSearchResponse struct {
 	Units []Unit `json:"jednotky"`
 	Total int    `json:"total"`
 }
 
+// IsEmpty reports whether the search matched no units.
+func (r *UnitSearchResponse) IsEmpty() bool {
+	return len(r.Units) == 0
+}
+
+// ProceedingSearchResponse wraps a list of proceedings.
+type ProceedingSearchResponse struct {
+	Proceedings []Proceeding `json:"rizeni"`
+	Total       int          `json:"total"`
+}
+
+// IsEmpty reports whether no proceedings were found.
+func (r *ProceedingSearchResponse) IsEmpty() bool {
+	return len(r.Proceedings) == 0
+}
+
 // NeighborParcelsResponse contains a list of neighboring parcels.
 type NeighborParcelsResponse struct {
 	ParcelID  int64    `json:"parcelaId"`
@@ -0,0 +1,31 @@
+package cuzk
+
+import "context"
+
+// Object kinds passed to an Observer.
+const (
+	ObjectParcel   = "parcel"
+	ObjectBuilding = "building"
+	ObjectUnit     = "unit"
+)
+
+// Observer is told about every parcel, building and unit detail the client
+// fetches, e.g. to keep a version history. Observe runs on the caller's
+// goroutine, so it should be quick and must not call back into the client.
+type Observer interface {
+	Observe(ctx context.Context, kind string, id int64, object any)
+}
+
+// WithObserver reports every fetched object detail to o.
+func WithObserver(o Observer) Option {
+	return func(c *Client) {
+		c.observer = o
+	}
+}
+
+// observe reports a fetched object to the observer, if any.
+func (c *Client) observe(ctx context.Context, kind string, id int64, object any) {
+	if c.observer != nil {
+		c.observer.Observe(ctx, kind, id, object)
+	}
+}
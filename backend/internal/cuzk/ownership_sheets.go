@@ -0,0 +1,19 @@
+package cuzk
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetOwnershipSheet returns the ownership sheet (LV) with the given number in a cadastral area.
+// LV numbers are only unique within a cadastral area.
+func (c *Client) GetOwnershipSheet(ctx context.Context, areaCode, number int) (*OwnershipSheet, error) {
+	path := fmt.Sprintf("/ListyVlastnictvi/%d/%d", areaCode, number)
+	var lv OwnershipSheet
+	if err := c.get(ctx, path, &lv); err != nil {
+		return nil, fromLocal(ctx, c, "ownership sheet", fmt.Errorf("get ownership sheet: %w", err), func(l LocalSource) (*OwnershipSheet, error) {
+			return l.LocalOwnershipSheet(ctx, areaCode, number)
+		})
+	}
+	return &lv, nil
+}
@@ -0,0 +1,103 @@
+package cuzk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parcel numbering types (druh číslování parcel).
+const (
+	NumberingBuilding = "stavební parcela"  // "st. 1234", building plot
+	NumberingLand     = "pozemková parcela" // "1234", land parcel
+)
+
+// ParcelNumber is a parcel designation within a cadastral area:
+// base number (kmenové číslo), optional subdivision (poddělení) and numbering type.
+type ParcelNumber struct {
+	Base        int
+	Subdivision int    // 0 when the parcel is not subdivided or any subdivision matches
+	Type        string // NumberingBuilding, NumberingLand or "" for either
+}
+
+// buildingPrefixes and landPrefixes mark the numbering type in a designation.
+var (
+	buildingPrefixes = []string{"st.", "st ", "stavební"}
+	landPrefixes     = []string{"p.č.", "parc. č.", "parc.č.", "pozemková"}
+)
+
+// ParseParcelNumber parses designations like "st. 1234/5", "1234/5", "p.č. 1234" or "1234".
+// Without a prefix the numbering type is left open, since users commonly omit "st.".
+func ParseParcelNumber(s string) (ParcelNumber, error) {
+	var n ParcelNumber
+	rest := strings.TrimSpace(strings.ToLower(s))
+	if rest == "" {
+		return n, errors.New("empty parcel number")
+	}
+
+	for _, p := range buildingPrefixes {
+		if after, ok := strings.CutPrefix(rest, p); ok {
+			n.Type, rest = NumberingBuilding, after
+			break
+		}
+	}
+	if n.Type == "" {
+		for _, p := range landPrefixes {
+			if after, ok := strings.CutPrefix(rest, p); ok {
+				n.Type, rest = NumberingLand, after
+				break
+			}
+		}
+	}
+	rest = strings.TrimSpace(rest)
+
+	base, sub, hasSub := strings.Cut(rest, "/")
+	var err error
+	if n.Base, err = parsePositive(base); err != nil {
+		return ParcelNumber{}, fmt.Errorf("invalid parcel number %q: base %w", s, err)
+	}
+	if hasSub {
+		if n.Subdivision, err = parsePositive(sub); err != nil {
+			return ParcelNumber{}, fmt.Errorf("invalid parcel number %q: subdivision %w", s, err)
+		}
+	}
+	return n, nil
+}
+
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return 0, errors.New("must be a positive integer")
+	}
+	return n, nil
+}
+
+// String formats the designation canonically: "st. 1234/5" for building plots,
+// "p.č. 1234/5" for land parcels and "1234/5" when the type is open.
+// ParseParcelNumber(n.String()) == n.
+func (n ParcelNumber) String() string {
+	var b strings.Builder
+	switch n.Type {
+	case NumberingBuilding:
+		b.WriteString("st. ")
+	case NumberingLand:
+		b.WriteString("p.č. ")
+	}
+	b.WriteString(strconv.Itoa(n.Base))
+	if n.Subdivision > 0 {
+		fmt.Fprintf(&b, "/%d", n.Subdivision)
+	}
+	return b.String()
+}
+
+// Matches reports whether p has this designation. An unset Subdivision or Type matches any.
+func (n ParcelNumber) Matches(p Parcel) bool {
+	if p.BaseNumber != n.Base {
+		return false
+	}
+	if n.Subdivision > 0 && (p.Subdivision == nil || *p.Subdivision != n.Subdivision) {
+		return false
+	}
+	return n.Type == "" || p.NumberingType == n.Type
+}
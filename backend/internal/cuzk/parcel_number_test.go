@@ -0,0 +1,32 @@
+package cuzk
+
+import "testing"
+
+func TestParseParcelNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ParcelNumber
+	}{
+		{"1234", ParcelNumber{Base: 1234}},
+		{"1234/5", ParcelNumber{Base: 1234, Subdivision: 5}},
+		{"st. 1234/5", ParcelNumber{Base: 1234, Subdivision: 5, Type: NumberingBuilding}},
+		{"St.1234", ParcelNumber{Base: 1234, Type: NumberingBuilding}},
+		{" p.č. 77 / 2 ", ParcelNumber{Base: 77, Subdivision: 2, Type: NumberingLand}},
+	}
+	for _, tt := range tests {
+		got, err := ParseParcelNumber(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseParcelNumber(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+			continue
+		}
+		if back, _ := ParseParcelNumber(got.String()); back != got {
+			t.Errorf("%q does not round-trip through String() %q", tt.in, got.String())
+		}
+	}
+
+	for _, in := range []string{"", "st.", "abc", "12/", "0", "12/-1", "1234/5/6"} {
+		if _, err := ParseParcelNumber(in); err == nil {
+			t.Errorf("ParseParcelNumber(%q): expected error", in)
+		}
+	}
+}
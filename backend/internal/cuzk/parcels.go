@@ -5,14 +5,43 @@ import (
 	"fmt"
 )
 
-// SearchParcels searches for parcels by cadastral area code and number.
-func (c *Client) SearchParcels(ctx context.Context, areaCode int, number string) (*ParcelSearchResponse, error) {
-	path := fmt.Sprintf("/Parcely/Vyhledani?katastralniUzemi=%d&kmenoveCislo=%s", areaCode, number)
-	var resp ParcelSearchResponse
-	if err := c.get(ctx, path, &resp); err != nil {
-		return nil, fmt.Errorf("search parcels: %w", err)
+// SearchParcels searches for parcels by cadastral area code and parcel designation.
+// CUZK matches on base number and subdivision; the numbering type (and the
+// subdivision, in case the upstream ignores it) is filtered here.
+func (c *Client) SearchParcels(ctx context.Context, areaCode int, number ParcelNumber) (*ParcelSearchResponse, error) {
+	path := fmt.Sprintf("/Parcely/Vyhledani?katastralniUzemi=%d&kmenoveCislo=%d", areaCode, number.Base)
+	if number.Subdivision > 0 {
+		path += fmt.Sprintf("&poddeleni=%d", number.Subdivision)
 	}
-	return &resp, nil
+	resp := &ParcelSearchResponse{}
+	if err := c.get(ctx, path, resp); err != nil {
+		return nil, fromLocal(ctx, c, "parcel search", fmt.Errorf("search parcels: %w", err), func(l LocalSource) (*ParcelSearchResponse, error) {
+			resp, err := l.LocalParcels(ctx, areaCode, number)
+			if resp == nil || err != nil {
+				return nil, err
+			}
+			// An empty local result says nothing; the CUZK error stands.
+			matchParcels(resp, number)
+			if resp.Total == 0 {
+				return nil, nil
+			}
+			return resp, nil
+		})
+	}
+	matchParcels(resp, number)
+	return resp, nil
+}
+
+// matchParcels keeps the parcels of resp that match number.
+func matchParcels(resp *ParcelSearchResponse, number ParcelNumber) {
+	matched := resp.Parcels[:0]
+	for _, p := range resp.Parcels {
+		if number.Matches(p) {
+			matched = append(matched, p)
+		}
+	}
+	resp.Parcels = matched
+	resp.Total = len(matched)
 }
 
 // GetParcel returns parcel detail by ISKN ID.
@@ -20,8 +49,9 @@ func (c *Client) GetParcel(ctx context.Context, id int64) (*Parcel, error) {
 	path := fmt.Sprintf("/Parcely/%d", id)
 	var p Parcel
 	if err := c.get(ctx, path, &p); err != nil {
-		return nil, fmt.Errorf("get parcel: %w", err)
+		return nil, fromLocal(ctx, c, "parcel", fmt.Errorf("get parcel: %w", err), func(l LocalSource) (*Parcel, error) { return l.LocalParcel(ctx, id) })
 	}
+	c.observe(ctx, ObjectParcel, id, &p)
 	return &p, nil
 }
 
@@ -46,7 +76,7 @@ func (c *Client) NeighborParcels(ctx context.Context, id int64) (*NeighborParcel
 	path := fmt.Sprintf("/Parcely/SousedniParcely/%d", id)
 	var resp NeighborParcelsResponse
 	if err := c.get(ctx, path, &resp); err != nil {
-		return nil, fmt.Errorf("neighbor parcels: %w", err)
+		return nil, fromLocal(ctx, c, "parcel neighbors", fmt.Errorf("neighbor parcels: %w", err), func(l LocalSource) (*NeighborParcelsResponse, error) { return l.LocalNeighbors(ctx, id) })
 	}
 	return &resp, nil
 }
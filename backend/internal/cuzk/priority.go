@@ -0,0 +1,145 @@
+package cuzk
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Priority decides the order in which queued calls get the rate limiter.
+type Priority int
+
+const (
+	// PriorityInteractive is for requests a user is waiting on; the default.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is for refreshes nobody is waiting on, e.g. stale cache revalidation.
+	PriorityBackground
+	// PriorityBulk is for batch jobs such as cache warming; it only uses capacity
+	// interactive calls leave and shares it with background calls.
+	PriorityBulk
+
+	numPriorities = iota
+)
+
+// priorityWeights are the shares of the turns interactive calls leave over:
+// while both have callers waiting, 3 of 4 go to background calls and 1 to
+// bulk, so a long warming run cannot starve revalidation or vice versa.
+// Interactive calls always go first and have no weight.
+var priorityWeights = [numPriorities]int{
+	PriorityBackground: 3,
+	PriorityBulk:       1,
+}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBackground:
+		return "background"
+	case PriorityBulk:
+		return "bulk"
+	}
+	return "unknown"
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context whose CUZK calls are queued at priority p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority stored in ctx, PriorityInteractive if none.
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= 0 && p < numPriorities {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// priorityQueue admits one caller at a time to the rate limiter. Interactive
+// callers always go first; the remaining turns are shared between background
+// and bulk callers by smooth weighted round-robin on priorityWeights. Callers
+// of one priority are served FIFO and a lone caller goes straight through.
+type priorityQueue struct {
+	mu     sync.Mutex
+	busy   bool
+	queues [numPriorities][]chan struct{}
+	credit [numPriorities]int // smooth weighted round-robin state
+}
+
+// acquire blocks until the caller is at the head of the queue. The caller
+// must call release once it has its limiter token.
+func (q *priorityQueue) acquire(ctx context.Context, p Priority) error {
+	q.mu.Lock()
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	q.queues[p] = append(q.queues[p], ready)
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+		q.mu.Lock()
+		i := slices.Index(q.queues[p], ready)
+		if i >= 0 {
+			q.queues[p] = slices.Delete(q.queues[p], i, i+1)
+		}
+		q.mu.Unlock()
+		if i < 0 {
+			// Handed the turn while giving up; pass it on.
+			q.release()
+		}
+		return ctx.Err()
+	}
+}
+
+// release hands the turn to the next waiter: the first interactive one, or
+// else the background or bulk waiter whose priority has the most credit (the
+// higher priority on a tie). Each of them with waiters earns its weight in
+// credit and the one served pays back the weights of all contenders.
+func (q *priorityQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if waiters := q.queues[PriorityInteractive]; len(waiters) > 0 {
+		q.queues[PriorityInteractive] = waiters[1:]
+		close(waiters[0])
+		return
+	}
+	best, total := -1, 0
+	for p := PriorityBackground; p < numPriorities; p++ {
+		if len(q.queues[p]) == 0 {
+			q.credit[p] = 0 // no credit saved up while idle
+			continue
+		}
+		q.credit[p] += priorityWeights[p]
+		total += priorityWeights[p]
+		if best < 0 || q.credit[p] > q.credit[best] {
+			best = int(p)
+		}
+	}
+	if best < 0 {
+		q.busy = false
+		return
+	}
+	q.credit[best] -= total
+	next := q.queues[best][0]
+	q.queues[best] = q.queues[best][1:]
+	close(next)
+}
+
+// waiting returns the number of queued callers per priority name.
+func (q *priorityQueue) waiting() map[string]int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make(map[string]int, numPriorities)
+	for p := range Priority(numPriorities) {
+		out[p.String()] = len(q.queues[p])
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package cuzk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueueServesInteractiveFirst(t *testing.T) {
+	var q priorityQueue
+	ctx := context.Background()
+	if err := q.acquire(ctx, PriorityBulk); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan Priority, 3)
+	enqueue := func(p Priority) {
+		go func() {
+			if err := q.acquire(ctx, p); err == nil {
+				order <- p
+				q.release()
+			}
+		}()
+		// Wait until the caller is queued so the enqueue order is fixed.
+		for q.waiting()[p.String()] == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	enqueue(PriorityBulk)
+	enqueue(PriorityBackground)
+	enqueue(PriorityInteractive)
+
+	q.release()
+	for _, want := range []Priority{PriorityInteractive, PriorityBackground, PriorityBulk} {
+		if got := <-order; got != want {
+			t.Fatalf("served %s, want %s", got, want)
+		}
+	}
+}
+
+func TestPriorityQueueSharesSpareTurnsByWeight(t *testing.T) {
+	var q priorityQueue
+	ctx := context.Background()
+	q.acquire(ctx, PriorityInteractive)
+
+	// Keep 8 interactive, 24 background and 24 bulk callers waiting and
+	// count who is served.
+	served := make(chan Priority, 56)
+	for _, w := range []struct {
+		p Priority
+		n int
+	}{{PriorityBulk, 24}, {PriorityBackground, 24}, {PriorityInteractive, 8}} {
+		for range w.n {
+			go func() {
+				if q.acquire(ctx, w.p) == nil {
+					served <- w.p
+				}
+			}()
+		}
+		for q.waiting()[w.p.String()] < w.n {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	var counts [numPriorities]int
+	for i := range 8 + 16 {
+		q.release()
+		p := <-served
+		if i < 8 && p != PriorityInteractive {
+			t.Fatalf("turn %d went to %s while interactive callers waited", i, p)
+		}
+		counts[p]++
+	}
+	// Background weighs 3 against bulk's 1: bulk gets 4 of 16 spare turns, not 0.
+	if counts[PriorityBackground] != 12 || counts[PriorityBulk] != 4 {
+		t.Errorf("background served %d, bulk %d of 16 spare turns, want 12 and 4",
+			counts[PriorityBackground], counts[PriorityBulk])
+	}
+}
+
+func TestPriorityQueueCanceledWaiter(t *testing.T) {
+	var q priorityQueue
+	q.acquire(context.Background(), PriorityInteractive)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.acquire(ctx, PriorityBulk); err == nil {
+		t.Fatal("expected canceled acquire to fail")
+	}
+	if n := q.waiting()["bulk"]; n != 0 {
+		t.Errorf("canceled waiter still queued: %d", n)
+	}
+
+	q.release()
+	if err := q.acquire(context.Background(), PriorityBulk); err != nil {
+		t.Fatalf("queue not released: %v", err)
+	}
+}
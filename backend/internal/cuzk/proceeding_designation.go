@@ -0,0 +1,51 @@
+package cuzk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ProceedingDesignation is the human-readable number of a proceeding (číslo řízení),
+// e.g. "V-1234/2025-101": type V (vklad), sequence 1234, year 2025, office 101.
+// In searches a zero Sequence or empty Type matches any.
+type ProceedingDesignation struct {
+	Type     string // V, Z, PGP, OR, PÚ, ...
+	Sequence int
+	Year     int
+	Office   string // three-digit office code (pracoviště), e.g. "101" for Praha
+}
+
+var designationRe = regexp.MustCompile(`^([A-ZÁČĎÉĚÍŇÓŘŠŤÚŮÝŽ]{1,4})\s*-\s*(\d+)\s*/\s*(\d{4})\s*-\s*(\d{3})$`)
+
+// ParseProceedingDesignation parses a designation like "V-1234/2025-101". Case and
+// spaces around the separators are ignored.
+func ParseProceedingDesignation(s string) (ProceedingDesignation, error) {
+	m := designationRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return ProceedingDesignation{}, fmt.Errorf("invalid proceeding designation %q, expected e.g. V-1234/2025-101", s)
+	}
+	seq, _ := strconv.Atoi(m[2])
+	year, _ := strconv.Atoi(m[3])
+	if seq <= 0 {
+		return ProceedingDesignation{}, fmt.Errorf("invalid proceeding designation %q: sequence must be positive", s)
+	}
+	return ProceedingDesignation{Type: m[1], Sequence: seq, Year: year, Office: m[4]}, nil
+}
+
+func (d ProceedingDesignation) String() string {
+	return fmt.Sprintf("%s-%d/%d-%s", d.Type, d.Sequence, d.Year, d.Office)
+}
+
+// Matches reports whether p has this designation. An empty Type or zero Sequence matches any.
+func (d ProceedingDesignation) Matches(p Proceeding) bool {
+	return p.Year == d.Year && p.Office == d.Office &&
+		(d.Type == "" || p.Type == d.Type) &&
+		(d.Sequence == 0 || p.SequenceNumber == d.Sequence)
+}
+
+// Designation returns the human-readable number of the proceeding.
+func (p Proceeding) Designation() ProceedingDesignation {
+	return ProceedingDesignation{Type: p.Type, Sequence: p.SequenceNumber, Year: p.Year, Office: p.Office}
+}
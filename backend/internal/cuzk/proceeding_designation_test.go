@@ -0,0 +1,31 @@
+package cuzk
+
+import "testing"
+
+func TestParseProceedingDesignation(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ProceedingDesignation
+	}{
+		{"V-1234/2025-101", ProceedingDesignation{Type: "V", Sequence: 1234, Year: 2025, Office: "101"}},
+		{" z - 377 / 2026 - 101 ", ProceedingDesignation{Type: "Z", Sequence: 377, Year: 2026, Office: "101"}},
+		{"PGP-88/2024-101", ProceedingDesignation{Type: "PGP", Sequence: 88, Year: 2024, Office: "101"}},
+		{"pú-5/2023-106", ProceedingDesignation{Type: "PÚ", Sequence: 5, Year: 2023, Office: "106"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseProceedingDesignation(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseProceedingDesignation(%q) = %+v, %v; want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "1234/2025-101", "V-1234-2025-101", "V-0/2025-101", "V-12/25-101", "V-12/2025-1011", "V12/2025"} {
+		if _, err := ParseProceedingDesignation(in); err == nil {
+			t.Errorf("ParseProceedingDesignation(%q): expected error", in)
+		}
+	}
+
+	if s := (ProceedingDesignation{Type: "V", Sequence: 1234, Year: 2025, Office: "101"}).String(); s != "V-1234/2025-101" {
+		t.Errorf("String() = %q", s)
+	}
+}
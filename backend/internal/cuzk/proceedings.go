@@ -3,6 +3,8 @@ package cuzk
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 )
 
 // GetProceeding returns proceeding detail by ISKN ID.
@@ -14,3 +16,90 @@ func (c *Client) GetProceeding(ctx context.Context, id int64) (*Proceeding, erro
 	}
 	return &p, nil
 }
+
+// SearchProceedings finds proceedings by designation. Year and Office are required;
+// an empty Type or zero Sequence lists every proceeding of that office and year.
+func (c *Client) SearchProceedings(ctx context.Context, d ProceedingDesignation) (*ProceedingSearchResponse, error) {
+	q := url.Values{}
+	q.Set("rok", strconv.Itoa(d.Year))
+	q.Set("pracoviste", d.Office)
+	if d.Type != "" {
+		q.Set("typRizeni", d.Type)
+	}
+	if d.Sequence > 0 {
+		q.Set("poradoveCislo", strconv.Itoa(d.Sequence))
+	}
+	var resp ProceedingSearchResponse
+	if err := c.get(ctx, "/Rizeni/Vyhledani?"+q.Encode(), &resp); err != nil {
+		return nil, fmt.Errorf("search proceedings: %w", err)
+	}
+	return &resp, nil
+}
+
+// GetParcelProceedings lists the proceedings affecting a parcel.
+func (c *Client) GetParcelProceedings(ctx context.Context, id int64) (*ProceedingSearchResponse, error) {
+	return c.getObjectProceedings(ctx, "Parcely", id)
+}
+
+// GetBuildingProceedings lists the proceedings affecting a building.
+func (c *Client) GetBuildingProceedings(ctx context.Context, id int64) (*ProceedingSearchResponse, error) {
+	return c.getObjectProceedings(ctx, "Stavby", id)
+}
+
+// GetUnitProceedings lists the proceedings affecting a unit.
+func (c *Client) GetUnitProceedings(ctx context.Context, id int64) (*ProceedingSearchResponse, error) {
+	return c.getObjectProceedings(ctx, "Jednotky", id)
+}
+
+func (c *Client) getObjectProceedings(ctx context.Context, collection string, id int64) (*ProceedingSearchResponse, error) {
+	path := fmt.Sprintf("/%s/%d/Rizeni", collection, id)
+	var resp ProceedingSearchResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("list proceedings: %w", err)
+	}
+	return &resp, nil
+}
+
+// GetProceedingOperations returns the timeline of a proceeding, oldest first.
+func (c *Client) GetProceedingOperations(ctx context.Context, id int64) ([]ProceedingOperation, error) {
+	var ops []ProceedingOperation
+	if err := c.get(ctx, fmt.Sprintf("/Rizeni/%d/Operace", id), &ops); err != nil {
+		return nil, fmt.Errorf("get proceeding operations: %w", err)
+	}
+	return nonNil(ops), nil
+}
+
+// GetProceedingParticipants returns the parties to a proceeding.
+func (c *Client) GetProceedingParticipants(ctx context.Context, id int64) ([]ProceedingParticipant, error) {
+	var parts []ProceedingParticipant
+	if err := c.get(ctx, fmt.Sprintf("/Rizeni/%d/Ucastnici", id), &parts); err != nil {
+		return nil, fmt.Errorf("get proceeding participants: %w", err)
+	}
+	return nonNil(parts), nil
+}
+
+// GetProceedingObjects returns the parcels, buildings and units a proceeding affects.
+func (c *Client) GetProceedingObjects(ctx context.Context, id int64) ([]ProceedingObject, error) {
+	var objs []ProceedingObject
+	if err := c.get(ctx, fmt.Sprintf("/Rizeni/%d/Predmety", id), &objs); err != nil {
+		return nil, fmt.Errorf("get proceeding objects: %w", err)
+	}
+	return nonNil(objs), nil
+}
+
+// GetProceedingDocuments returns the deeds and decisions filed in a proceeding.
+func (c *Client) GetProceedingDocuments(ctx context.Context, id int64) ([]ProceedingDocument, error) {
+	var docs []ProceedingDocument
+	if err := c.get(ctx, fmt.Sprintf("/Rizeni/%d/Listiny", id), &docs); err != nil {
+		return nil, fmt.Errorf("get proceeding documents: %w", err)
+	}
+	return nonNil(docs), nil
+}
+
+// nonNil turns a missing JSON list into an empty one so it encodes as [].
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
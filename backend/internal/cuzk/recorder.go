@@ -0,0 +1,164 @@
+package cuzk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoFixture is returned in replay mode when no fixture matches a request.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixture is a recorded CUZK request/response pair as stored on disk.
+type Fixture struct {
+	Method   string          `json:"method"`
+	Path     string          `json:"path"`
+	Status   int             `json:"status"`
+	Header   http.Header     `json:"header,omitempty"`
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"bodyText,omitempty"` // non-JSON bodies
+}
+
+// FixtureName returns the deterministic file name for a request.
+// pathQuery is relative to the client's base URL, e.g. "/Parcely/123".
+// A short hash keeps names unique after sanitizing.
+func FixtureName(method, pathQuery string) string {
+	key := method + " " + pathQuery
+	var b strings.Builder
+	b.WriteString(method)
+	for _, r := range pathQuery {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '=':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := b.String()
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("%s_%x.json", name, sum[:4])
+}
+
+// fixtureTransport records or replays CUZK traffic.
+// In record mode next performs the real request; in replay mode next is nil.
+type fixtureTransport struct {
+	dir      string
+	basePath string
+	next     http.RoundTripper
+}
+
+func newFixtureTransport(baseURL, dir string, next http.RoundTripper) *fixtureTransport {
+	basePath := ""
+	if u, err := url.Parse(baseURL); err == nil {
+		basePath = strings.TrimSuffix(u.Path, "/")
+	}
+	return &fixtureTransport{dir: dir, basePath: basePath, next: next}
+}
+
+func (t *fixtureTransport) relativePath(u *url.URL) string {
+	return strings.TrimPrefix(u.RequestURI(), t.basePath)
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := t.relativePath(req.URL)
+	file := filepath.Join(t.dir, FixtureName(req.Method, path))
+
+	if t.next == nil {
+		return t.replay(req, file)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	f := Fixture{
+		Method: req.Method,
+		Path:   path,
+		Status: resp.StatusCode,
+		Header: recordedHeaders(resp.Header),
+	}
+	if json.Valid(body) {
+		f.Body = body
+	} else {
+		f.BodyText = string(body)
+	}
+	if err := writeFixture(file, &f); err != nil {
+		return nil, fmt.Errorf("record fixture: %w", err)
+	}
+	return resp, nil
+}
+
+func (t *fixtureTransport) replay(req *http.Request, file string) (*http.Response, error) {
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoFixture, req.Method, t.relativePath(req.URL))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", file, err)
+	}
+
+	body := []byte(f.Body)
+	if f.BodyText != "" {
+		body = []byte(f.BodyText)
+	}
+	header := f.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Status, http.StatusText(f.Status)),
+		StatusCode:    f.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// recordedHeaders keeps only headers that influence client behaviour.
+func recordedHeaders(h http.Header) http.Header {
+	out := http.Header{}
+	for _, k := range []string{"Content-Type", "Retry-After"} {
+		if v := h.Get(k); v != "" {
+			out.Set(k, v)
+		}
+	}
+	return out
+}
+
+func writeFixture(file string, f *Fixture) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,46 @@
+package cuzk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/cuzkmock"
+)
+
+func TestFixtureNameDeterministic(t *testing.T) {
+	a := cuzk.FixtureName("GET", "/Parcely/Vyhledani?katastralniUzemi=729272&kmenoveCislo=1521")
+	b := cuzk.FixtureName("GET", "/Parcely/Vyhledani?katastralniUzemi=729272&kmenoveCislo=1521")
+	c := cuzk.FixtureName("GET", "/Parcely/Vyhledani?katastralniUzemi=729272&kmenoveCislo=1520")
+	if a != b {
+		t.Errorf("same request produced different names: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Errorf("different requests share name %s", a)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	dir := t.TempDir()
+	_, ts := cuzkmock.NewTestServer("")
+	rec := cuzk.NewClient(ts.URL, "", cuzk.WithRecording(dir))
+	want, err := rec.GetParcel(context.Background(), 729272102)
+	ts.Close() // replay below must not need the upstream
+	if err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	replay := cuzk.NewClient("http://127.0.0.1:0", "", cuzk.WithReplay(dir))
+	got, err := replay.GetParcel(context.Background(), 729272102)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if got.ID != want.ID || got.Area != want.Area || *got.Subdivision != *want.Subdivision {
+		t.Errorf("replayed %+v, recorded %+v", got, want)
+	}
+
+	if _, err := replay.GetParcel(context.Background(), 1); !errors.Is(err, cuzk.ErrNoFixture) {
+		t.Errorf("expected ErrNoFixture for unrecorded request, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package cuzk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// GetParcelRights returns the rights and restrictions recorded against a parcel.
+func (c *Client) GetParcelRights(ctx context.Context, id int64) (*RightsResponse, error) {
+	return c.getRights(ctx, "Parcely", id)
+}
+
+// GetBuildingRights returns the rights and restrictions recorded against a building.
+func (c *Client) GetBuildingRights(ctx context.Context, id int64) (*RightsResponse, error) {
+	return c.getRights(ctx, "Stavby", id)
+}
+
+// GetUnitRights returns the rights and restrictions recorded against a unit.
+func (c *Client) GetUnitRights(ctx context.Context, id int64) (*RightsResponse, error) {
+	return c.getRights(ctx, "Jednotky", id)
+}
+
+func (c *Client) getRights(ctx context.Context, collection string, id int64) (*RightsResponse, error) {
+	path := fmt.Sprintf("/%s/%d/PravniVztahy", collection, id)
+	var resp RightsResponse
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("get rights: %w", err)
+	}
+	resp.Rights = nonNil(resp.Rights)
+	for i := range resp.Rights {
+		resp.Rights[i].Category = CategorizeRight(resp.Rights[i].Type)
+		resp.Rights[i].ProceedingIDs = nonNil(resp.Rights[i].ProceedingIDs)
+	}
+	return &resp, nil
+}
+
+// CategorizeRight maps a recorded right type such as "věcné břemeno chůze a jízdy" to its category.
+func CategorizeRight(typ string) RightCategory {
+	t := strings.ToLower(typ)
+	switch {
+	case strings.Contains(t, "břemeno"), strings.Contains(t, "služebnost"):
+		return RightEasement
+	case strings.Contains(t, "zástavní"):
+		return RightLien
+	case strings.Contains(t, "předkupní"):
+		return RightPreemption
+	}
+	return RightOther
+}
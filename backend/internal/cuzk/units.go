@@ -20,7 +20,8 @@ func (c *Client) GetUnit(ctx context.Context, id int64) (*Unit, error) {
 	path := fmt.Sprintf("/Jednotky/%d", id)
 	var u Unit
 	if err := c.get(ctx, path, &u); err != nil {
-		return nil, fmt.Errorf("get unit: %w", err)
+		return nil, fromLocal(ctx, c, "unit", fmt.Errorf("get unit: %w", err), func(l LocalSource) (*Unit, error) { return l.LocalUnit(ctx, id) })
 	}
+	c.observe(ctx, ObjectUnit, id, &u)
 	return &u, nil
 }
@@ -0,0 +1,165 @@
+package cuzkmock
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+//go:embed fixtures/prague6.json
+var prague6JSON []byte
+
+// Dataset is the fixture data served by the mock.
+// It uses the cuzk models directly so responses decode exactly like the real API.
+type Dataset struct {
+	Parcels     []cuzk.Parcel      `json:"parcely"`
+	Buildings   []cuzk.Building    `json:"stavby"`
+	Units       []cuzk.Unit        `json:"jednotky"`
+	Proceedings []cuzk.Proceeding  `json:"rizeni"`
+	Neighbors   map[string][]int64 `json:"sousedniParcely"`
+	// OwnershipSheets hold owners and encumbrances only; the parcels,
+	// buildings and units on a sheet are collected by their cisloLV.
+	OwnershipSheets []cuzk.OwnershipSheet `json:"listyVlastnictvi"`
+	// Rights maps a parcel, building or unit ID to the rights recorded against it.
+	Rights map[string][]cuzk.Right `json:"pravniVztahy"`
+	// ProceedingDetails holds the operations, participants, objects and
+	// documents of a proceeding, keyed by proceeding ID.
+	ProceedingDetails map[string]cuzk.Proceeding `json:"rizeniDetail"`
+}
+
+// Prague6 returns the embedded Prague 6 fixture dataset.
+func Prague6() *Dataset {
+	d, err := parseDataset(prague6JSON)
+	if err != nil {
+		panic(fmt.Sprintf("cuzkmock: embedded fixtures: %v", err))
+	}
+	return d
+}
+
+// LoadDataset reads a fixture dataset from a JSON file.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseDataset(data)
+}
+
+func parseDataset(data []byte) (*Dataset, error) {
+	var d Dataset
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("json decode: %w", err)
+	}
+	return &d, nil
+}
+
+func (d *Dataset) parcel(id int64) (*cuzk.Parcel, bool) {
+	for i := range d.Parcels {
+		if d.Parcels[i].ID == id {
+			return &d.Parcels[i], true
+		}
+	}
+	return nil, false
+}
+
+func (d *Dataset) building(id int64) (*cuzk.Building, bool) {
+	for i := range d.Buildings {
+		if d.Buildings[i].ID == id {
+			return &d.Buildings[i], true
+		}
+	}
+	return nil, false
+}
+
+func (d *Dataset) unit(id int64) (*cuzk.Unit, bool) {
+	for i := range d.Units {
+		if d.Units[i].ID == id {
+			return &d.Units[i], true
+		}
+	}
+	return nil, false
+}
+
+func (d *Dataset) proceeding(id int64) (*cuzk.Proceeding, bool) {
+	for i := range d.Proceedings {
+		if d.Proceedings[i].ID == id {
+			return &d.Proceedings[i], true
+		}
+	}
+	return nil, false
+}
+
+// ownershipSheet assembles the sheet with the given number in a cadastral area.
+func (d *Dataset) ownershipSheet(areaCode int, number string) (*cuzk.OwnershipSheet, bool) {
+	onSheet := func(area *cuzk.CadastralArea, lv *string) bool {
+		return area != nil && area.Code == areaCode && lv != nil && *lv == number
+	}
+
+	for _, s := range d.OwnershipSheets {
+		if s.CadastralArea.Code != areaCode || s.Number != number {
+			continue
+		}
+		lv := s
+		lv.Parcels, lv.Buildings, lv.Units = []cuzk.Parcel{}, []cuzk.Building{}, []cuzk.Unit{}
+		for _, p := range d.Parcels {
+			if onSheet(&p.CadastralArea, p.OwnershipSheet) {
+				lv.Parcels = append(lv.Parcels, p)
+			}
+		}
+		for _, b := range d.Buildings {
+			if onSheet(&b.CadastralArea, b.OwnershipSheet) {
+				lv.Buildings = append(lv.Buildings, b)
+			}
+		}
+		for _, u := range d.Units {
+			if onSheet(u.CadastralArea, u.OwnershipSheet) {
+				lv.Units = append(lv.Units, u)
+			}
+		}
+		if lv.Encumbrances == nil {
+			lv.Encumbrances = []cuzk.Encumbrance{}
+		}
+		return &lv, true
+	}
+	return nil, false
+}
+
+// rights returns the rights recorded against an object; objects without any get an empty list.
+func (d *Dataset) rights(id int64) cuzk.RightsResponse {
+	rights := d.Rights[strconv.FormatInt(id, 10)]
+	if rights == nil {
+		rights = []cuzk.Right{}
+	}
+	return cuzk.RightsResponse{ObjectID: id, Rights: rights}
+}
+
+// objectProceedings lists the proceedings referenced by an object's rights.
+func (d *Dataset) objectProceedings(id int64) cuzk.ProceedingSearchResponse {
+	resp := cuzk.ProceedingSearchResponse{Proceedings: []cuzk.Proceeding{}}
+	seen := map[int64]bool{}
+	for _, right := range d.Rights[strconv.FormatInt(id, 10)] {
+		for _, pid := range right.ProceedingIDs {
+			if p, ok := d.proceeding(pid); ok && !seen[pid] {
+				seen[pid] = true
+				resp.Proceedings = append(resp.Proceedings, *p)
+			}
+		}
+	}
+	resp.Total = len(resp.Proceedings)
+	return resp
+}
+
+// buildingNumber returns the descriptive (č.p.) or evidence (č.e.) number as a string.
+func buildingNumber(b *cuzk.Building) string {
+	switch {
+	case b.DescriptiveNo != nil:
+		return strconv.Itoa(*b.DescriptiveNo)
+	case b.EvidenceNo != nil:
+		return strconv.Itoa(*b.EvidenceNo)
+	}
+	return ""
+}
@@ -0,0 +1,438 @@
+// Package cuzkmock is a local stand-in for the CUZK REST API.
+// It serves the same paths cuzk.Client calls from a fixture dataset,
+// checks the Api-Key header and can inject 429/5xx faults.
+package cuzkmock
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// Fault describes an injected upstream failure.
+type Fault struct {
+	Status int     // HTTP status to answer with, e.g. 429 or 503
+	Count  int     // number of requests to fail; 0 means until cleared
+	Rate   float64 // probability of failing a matching request; 0 means always
+	Path   string  // only requests whose path starts with Path; "" matches all
+
+	RetryAfter int // seconds sent in the Retry-After header; 0 omits it
+}
+
+// Server is an http.Handler emulating the CUZK REST API.
+type Server struct {
+	apiKey string
+	data   *Dataset
+	router chi.Router
+
+	mu       sync.Mutex
+	faults   []*Fault
+	requests int
+}
+
+// New creates a mock server for the given dataset.
+// If apiKey is non-empty, requests must carry it in the Api-Key header.
+func New(apiKey string, data *Dataset) *Server {
+	s := &Server{apiKey: apiKey, data: data}
+
+	r := chi.NewRouter()
+	r.Get("/Parcely/Vyhledani", s.searchParcels)
+	r.Get("/Parcely/Polygon", s.polygonParcels)
+	r.Get("/Parcely/SousedniParcely/{id}", s.neighborParcels)
+	r.Get("/Parcely/{id}", s.getParcel)
+	r.Get("/Parcely/{id}/PravniVztahy", s.getParcelRights)
+	r.Get("/Parcely/{id}/Rizeni", s.objectProceedings)
+	r.Get("/Stavby/Vyhledani", s.searchBuildings)
+	r.Get("/Stavby/{id}", s.getBuilding)
+	r.Get("/Stavby/{id}/PravniVztahy", s.getBuildingRights)
+	r.Get("/Stavby/{id}/Rizeni", s.objectProceedings)
+	r.Get("/Jednotky/Vyhledani", s.searchUnits)
+	r.Get("/Jednotky/{id}", s.getUnit)
+	r.Get("/Jednotky/{id}/PravniVztahy", s.getUnitRights)
+	r.Get("/Jednotky/{id}/Rizeni", s.objectProceedings)
+	r.Get("/Rizeni/Vyhledani", s.searchProceedings)
+	r.Get("/Rizeni/{id}", s.getProceeding)
+	r.Get("/Rizeni/{id}/Operace", s.proceedingPart(func(p cuzk.Proceeding) any { return p.Operations }))
+	r.Get("/Rizeni/{id}/Ucastnici", s.proceedingPart(func(p cuzk.Proceeding) any { return p.Participants }))
+	r.Get("/Rizeni/{id}/Predmety", s.proceedingPart(func(p cuzk.Proceeding) any { return p.Objects }))
+	r.Get("/Rizeni/{id}/Listiny", s.proceedingPart(func(p cuzk.Proceeding) any { return p.Documents }))
+	r.Get("/ListyVlastnictvi/{area}/{number}", s.getOwnershipSheet)
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Neznámý endpoint")
+	})
+	s.router = r
+
+	return s
+}
+
+// NewTestServer starts a mock with the Prague 6 fixtures on a loopback address.
+// The caller must Close the returned httptest.Server.
+func NewTestServer(apiKey string) (*Server, *httptest.Server) {
+	s := New(apiKey, Prague6())
+	return s, httptest.NewServer(s)
+}
+
+// InjectFault adds a fault rule. Rules are evaluated in insertion order.
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all fault rules.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the number of requests received so far, including rejected ones.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	if s.apiKey != "" && r.Header.Get("Api-Key") != s.apiKey {
+		writeError(w, http.StatusUnauthorized, "UNAUTHORIZED", "Neplatný nebo chybějící API klíč")
+		return
+	}
+
+	s.mu.Lock()
+	fault := s.matchFault(r.URL.Path)
+	s.mu.Unlock()
+	if fault != nil {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(fault.RetryAfter))
+		}
+		writeError(w, fault.Status, "INJECTED_FAULT", http.StatusText(fault.Status))
+		return
+	}
+
+	s.router.ServeHTTP(w, r)
+}
+
+// matchFault returns the first fault that fires for path, or nil.
+// Must be called with s.mu held.
+func (s *Server) matchFault(path string) *Fault {
+	for i, f := range s.faults {
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Rate > 0 && rand.Float64() >= f.Rate {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (s *Server) searchParcels(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	area, _ := strconv.Atoi(q.Get("katastralniUzemi"))
+	base, _ := strconv.Atoi(q.Get("kmenoveCislo"))
+	sub, _ := strconv.Atoi(q.Get("poddeleni"))
+	number := cuzk.ParcelNumber{Base: base, Subdivision: sub}
+
+	resp := cuzk.ParcelSearchResponse{Parcels: []cuzk.Parcel{}}
+	for _, p := range s.data.Parcels {
+		if p.CadastralArea.Code == area && number.Matches(p) {
+			resp.Parcels = append(resp.Parcels, p)
+		}
+	}
+	resp.Total = len(resp.Parcels)
+	writeJSON(w, resp)
+}
+
+func (s *Server) getParcel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	p, found := s.data.parcel(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Parcela "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, p)
+}
+
+// polygonParcels returns parcels whose reference point lies in the bounding box
+// of the souradniceX/souradniceY vertices.
+func (s *Server) polygonParcels(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	minX, maxX, okX := bounds(q["souradniceX"])
+	minY, maxY, okY := bounds(q["souradniceY"])
+	if !okX || !okY {
+		writeError(w, http.StatusBadRequest, "INVALID_POLYGON", "Neplatné souřadnice polygonu")
+		return
+	}
+
+	resp := cuzk.ParcelSearchResponse{Parcels: []cuzk.Parcel{}}
+	for _, p := range s.data.Parcels {
+		pt := p.ReferencePoint
+		if pt == nil {
+			continue
+		}
+		if pt.X >= minX && pt.X <= maxX && pt.Y >= minY && pt.Y <= maxY {
+			resp.Parcels = append(resp.Parcels, p)
+		}
+	}
+	resp.Total = len(resp.Parcels)
+	writeJSON(w, resp)
+}
+
+func (s *Server) neighborParcels(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if _, found := s.data.parcel(id); !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Parcela "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+
+	resp := cuzk.NeighborParcelsResponse{ParcelID: id, Neighbors: []cuzk.Parcel{}}
+	for _, nid := range s.data.Neighbors[strconv.FormatInt(id, 10)] {
+		if p, found := s.data.parcel(nid); found {
+			resp.Neighbors = append(resp.Neighbors, *p)
+		}
+	}
+	writeJSON(w, resp)
+}
+
+func (s *Server) searchBuildings(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	area, _ := strconv.Atoi(q.Get("katastralniUzemi"))
+	number := q.Get("cislo")
+
+	resp := cuzk.BuildingSearchResponse{Buildings: []cuzk.Building{}}
+	for _, b := range s.data.Buildings {
+		if b.CadastralArea.Code == area && buildingNumber(&b) == number {
+			resp.Buildings = append(resp.Buildings, b)
+		}
+	}
+	resp.Total = len(resp.Buildings)
+	writeJSON(w, resp)
+}
+
+func (s *Server) getBuilding(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	b, found := s.data.building(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Stavba "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, b)
+}
+
+// searchUnits matches cisloJednotky either in full ("1520/3") or as the part after the building number ("3").
+func (s *Server) searchUnits(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	area, _ := strconv.Atoi(q.Get("katastralniUzemi"))
+	buildingNo := q.Get("cisloStavby")
+	unitNo := q.Get("cisloJednotky")
+
+	resp := cuzk.UnitSearchResponse{Units: []cuzk.Unit{}}
+	for _, u := range s.data.Units {
+		if u.BuildingID == nil {
+			continue
+		}
+		b, found := s.data.building(*u.BuildingID)
+		if !found || b.CadastralArea.Code != area || buildingNumber(b) != buildingNo {
+			continue
+		}
+		if u.UnitNumber == unitNo || u.UnitNumber == buildingNo+"/"+unitNo {
+			resp.Units = append(resp.Units, u)
+		}
+	}
+	resp.Total = len(resp.Units)
+	writeJSON(w, resp)
+}
+
+func (s *Server) getUnit(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	u, found := s.data.unit(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Jednotka "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, u)
+}
+
+func (s *Server) getProceeding(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	p, found := s.data.proceeding(id)
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Řízení "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, p)
+}
+
+func (s *Server) getParcelRights(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if _, found := s.data.parcel(id); !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Parcela "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, s.data.rights(id))
+}
+
+func (s *Server) getBuildingRights(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if _, found := s.data.building(id); !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Stavba "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, s.data.rights(id))
+}
+
+func (s *Server) getUnitRights(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	if _, found := s.data.unit(id); !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "Jednotka "+strconv.FormatInt(id, 10)+" neexistuje")
+		return
+	}
+	writeJSON(w, s.data.rights(id))
+}
+
+// proceedingPart serves one list of a proceeding's detail; proceedings without
+// fixture detail answer with an empty list.
+func (s *Server) proceedingPart(part func(cuzk.Proceeding) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		if _, found := s.data.proceeding(id); !found {
+			writeError(w, http.StatusNotFound, "NOT_FOUND", "Řízení "+strconv.FormatInt(id, 10)+" neexistuje")
+			return
+		}
+		detail := s.data.ProceedingDetails[strconv.FormatInt(id, 10)]
+		if list := part(detail); !reflect.ValueOf(list).IsNil() {
+			writeJSON(w, list)
+			return
+		}
+		writeJSON(w, []any{})
+	}
+}
+
+func (s *Server) searchProceedings(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	year, _ := strconv.Atoi(q.Get("rok"))
+	seq, _ := strconv.Atoi(q.Get("poradoveCislo"))
+	d := cuzk.ProceedingDesignation{Type: q.Get("typRizeni"), Sequence: seq, Year: year, Office: q.Get("pracoviste")}
+
+	resp := cuzk.ProceedingSearchResponse{Proceedings: []cuzk.Proceeding{}}
+	for _, p := range s.data.Proceedings {
+		if d.Matches(p) {
+			resp.Proceedings = append(resp.Proceedings, p)
+		}
+	}
+	resp.Total = len(resp.Proceedings)
+	writeJSON(w, resp)
+}
+
+// objectProceedings serves /{Parcely|Stavby|Jednotky}/{id}/Rizeni. Unknown
+// objects get an empty list; the fixture IDs are unique across collections.
+func (s *Server) objectProceedings(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, s.data.objectProceedings(id))
+}
+
+func (s *Server) getOwnershipSheet(w http.ResponseWriter, r *http.Request) {
+	area, err := strconv.Atoi(chi.URLParam(r, "area"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Neplatný kód katastrálního území")
+		return
+	}
+	number := chi.URLParam(r, "number")
+	lv, found := s.data.ownershipSheet(area, number)
+	if !found {
+		writeError(w, http.StatusNotFound, "NOT_FOUND", "List vlastnictví "+number+" v k.ú. "+strconv.Itoa(area)+" neexistuje")
+		return
+	}
+	writeJSON(w, lv)
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_ID", "Neplatné ID")
+		return 0, false
+	}
+	return id, true
+}
+
+func bounds(values []string) (lo, hi float64, ok bool) {
+	if len(values) == 0 {
+		return 0, 0, false
+	}
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		lo, hi = min(lo, f), max(hi, f)
+	}
+	return lo, hi, true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with a CUZK-style error body.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{
+		"kod":    code,
+		"zprava": message,
+	})
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// entityPrefixes maps an entity name to the cache prefixes keyed by its ID.
+var entityPrefixes = map[string][]string{
+	"parcel":     {prefixParcel, prefixParcelNeighbors, prefixParcelRights, prefixParcelProceedings},
+	"building":   {prefixBuilding, prefixBuildingRights, prefixBuildingProceedings},
+	"unit":       {prefixUnit, prefixUnitRights, prefixUnitProceedings},
+	"proceeding": {prefixProceeding, prefixProceedingOperations, prefixProceedingParticipants, prefixProceedingObjects, prefixProceedingDocuments},
+}
+
+// AdminHandler exposes cache inspection and purge endpoints and CUZK key usage.
+type AdminHandler struct {
+	cache  cache.Store
+	token  string
+	client *cuzk.Client
+}
+
+// NewAdminHandler creates a new AdminHandler. token is the required bearer credential.
+func NewAdminHandler(c cache.Store, token string, client *cuzk.Client) *AdminHandler {
+	return &AdminHandler{cache: c, token: token, client: client}
+}
+
+// Authenticate rejects requests without "Authorization: Bearer <token>".
+func (h *AdminHandler) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || h.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(h.token)) != 1 {
+			writeError(w, r, http.StatusUnauthorized, ErrorBody{Code: "unauthorized", Message: "admin credential required"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// cacheEntryInfo describes one cache entry for the admin API.
+type cacheEntryInfo struct {
+	Key        string     `json:"key"`
+	TTLSeconds int64      `json:"ttlSeconds"`
+	SizeBytes  int        `json:"sizeBytes"`
+	Negative   bool       `json:"negative"`
+	FreshUntil *time.Time `json:"freshUntil,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Keys handles GET /api/admin/cache/keys?prefix={prefix}&limit={n}
+// prefix is relative to the cuzk: namespace, e.g. "parcel" or "parcels:search",
+// and matches whole key segments: "parcel" does not match "parcels:search".
+func (h *AdminHandler) Keys(w http.ResponseWriter, r *http.Request) {
+	if !h.requireCache(w, r) {
+		return
+	}
+
+	limit := 1000
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			writeValidationError(w, r, FieldError{Field: "limit", Message: "must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	keys, err := h.keys(r.Context(), r.URL.Query().Get("prefix"))
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+		return
+	}
+	slices.Sort(keys)
+	total := len(keys)
+	if len(keys) > limit {
+		keys = keys[:limit]
+	}
+
+	writeJSON(w, map[string]any{
+		"keys":  keys,
+		"total": total,
+	})
+}
+
+// Entry handles GET /api/admin/cache/entry?key={key}
+func (h *AdminHandler) Entry(w http.ResponseWriter, r *http.Request) {
+	if !h.requireCache(w, r) {
+		return
+	}
+	if missing := requiredParams(r, "key"); len(missing) > 0 {
+		writeValidationError(w, r, missing...)
+		return
+	}
+	key := r.URL.Query().Get("key")
+
+	value, err := h.cache.Get(r.Context(), key)
+	if errors.Is(err, cache.ErrMiss) {
+		writeError(w, r, http.StatusNotFound, ErrorBody{Code: CodeNotFound, Message: "no cache entry for " + key})
+		return
+	}
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+		return
+	}
+	ttl, _ := h.cache.TTL(r.Context(), key)
+
+	info := cacheEntryInfo{
+		Key:        key,
+		TTLSeconds: int64(ttl.Seconds()),
+		SizeBytes:  len(value),
+	}
+	var entry cacheEntry
+	if json.Unmarshal([]byte(value), &entry) == nil {
+		info.Negative = entry.negative()
+		info.FreshUntil = &entry.FreshUntil
+		info.ExpiresAt = &entry.ExpiresAt
+	}
+	writeJSON(w, info)
+}
+
+// PurgeEntity handles DELETE /api/admin/cache/{entity}/{id}
+// entity is one of parcel, building, unit, proceeding.
+func (h *AdminHandler) PurgeEntity(w http.ResponseWriter, r *http.Request) {
+	if !h.requireCache(w, r) {
+		return
+	}
+	entity := chi.URLParam(r, "entity")
+	prefixes, ok := entityPrefixes[entity]
+	if !ok {
+		writeValidationError(w, r, FieldError{Field: "entity", Message: "must be one of parcel, building, unit, proceeding"})
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return
+	}
+
+	// Only report the entries that were cached.
+	keys := make([]string, 0, len(prefixes))
+	for _, p := range prefixes {
+		key := CacheKey(p, id)
+		if _, err := h.cache.TTL(r.Context(), key); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	h.purge(w, r, keys)
+}
+
+// Purge handles DELETE /api/admin/cache?prefix={prefix} and DELETE /api/admin/cache?all=true
+// prefix matches whole key segments, as in Keys.
+func (h *AdminHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	if !h.requireCache(w, r) {
+		return
+	}
+	prefix := r.URL.Query().Get("prefix")
+	all := r.URL.Query().Get("all") == "true"
+	if prefix == "" && !all {
+		writeValidationError(w, r, FieldError{Field: "prefix", Message: "required unless all=true"})
+		return
+	}
+
+	keys, err := h.keys(r.Context(), prefix)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+		return
+	}
+	h.purge(w, r, keys)
+}
+
+// APIKeys handles GET /api/admin/cuzk/keys
+// Reports today's usage and remaining quota per CUZK API key; keys themselves are never shown.
+func (h *AdminHandler) APIKeys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"keys": h.client.KeyStats(r.Context()),
+	})
+}
+
+// keys returns the cache keys under prefix, relative to KeyNamespace. The
+// prefix must end at a segment boundary, so "parcel" matches "cuzk:parcel:1"
+// but not "cuzk:parcels:search:…"; an empty prefix matches every key.
+func (h *AdminHandler) keys(ctx context.Context, prefix string) ([]string, error) {
+	keys, err := h.cache.Keys(ctx, KeyNamespace+prefix)
+	if err != nil || prefix == "" || strings.HasSuffix(prefix, ":") {
+		return keys, err
+	}
+	full := KeyNamespace + prefix
+	return slices.DeleteFunc(keys, func(k string) bool {
+		return k != full && !strings.HasPrefix(k, full+":")
+	}), nil
+}
+
+func (h *AdminHandler) purge(w http.ResponseWriter, r *http.Request, keys []string) {
+	if err := h.cache.Delete(r.Context(), keys...); err != nil {
+		writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+		return
+	}
+	writeJSON(w, map[string]any{"deleted": keys})
+}
+
+func (h *AdminHandler) requireCache(w http.ResponseWriter, r *http.Request) bool {
+	if h.cache == nil {
+		writeError(w, r, http.StatusServiceUnavailable, ErrorBody{Code: "cache_disabled", Message: "caching is disabled"})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cache"
+)
+
+func TestCacheKeyReadable(t *testing.T) {
+	if got := CacheKey(prefixParcelSearch, 729272, "st. 1234/5"); got != "cuzk:parcels:search:729272:st.%201234/5" {
+		t.Errorf("unexpected key %q", got)
+	}
+	if got := CacheKey(prefixParcelPolygon, 1041306.07, 744800.86, 5); got != "cuzk:parcels:polygon:1041306:744801:5" {
+		t.Errorf("unexpected key %q", got)
+	}
+}
+
+func TestAdminPurgeEntity(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache(100, 0)
+	store.Set(ctx, CacheKey(prefixParcel, 1), "{}", time.Hour)
+	store.Set(ctx, CacheKey(prefixParcelNeighbors, 1), "{}", time.Hour)
+	store.Set(ctx, CacheKey(prefixParcel, 2), "{}", time.Hour)
+
+	h := NewAdminHandler(store, "secret", nil)
+	r := chi.NewRouter()
+	r.Use(h.Authenticate)
+	r.Delete("/cache/{entity}/{id}", h.PurgeEntity)
+
+	req := httptest.NewRequest(http.MethodDelete, "/cache/parcel/1", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct{ Deleted []string }
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Deleted) != 2 {
+		t.Errorf("expected only the cached parcel and neighbors keys reported, got %v", resp.Deleted)
+	}
+	keys, _ := store.Keys(ctx, KeyNamespace)
+	if len(keys) != 1 || keys[0] != "cuzk:parcel:2" {
+		t.Errorf("unexpected remaining keys %v", keys)
+	}
+}
+
+func TestAdminPurgeMatchesWholeSegments(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewMemoryCache(100, 0)
+	store.Set(ctx, CacheKey(prefixParcel, 1), "{}", time.Hour)
+	store.Set(ctx, CacheKey(prefixParcelSearch, 729272, "1521"), "{}", time.Hour)
+
+	h := NewAdminHandler(store, "secret", nil)
+	req := httptest.NewRequest(http.MethodDelete, "/cache?prefix=parcel", nil)
+	rec := httptest.NewRecorder()
+	h.Purge(rec, req)
+
+	var resp struct{ Deleted []string }
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Deleted) != 1 || resp.Deleted[0] != "cuzk:parcel:1" {
+		t.Errorf("expected only cuzk:parcel:1 purged, got %v", resp.Deleted)
+	}
+	if keys, _ := store.Keys(ctx, KeyNamespace+"parcels"); len(keys) != 1 {
+		t.Errorf("parcel search entry was purged: %v", keys)
+	}
+}
@@ -1,14 +1,12 @@
 package handler
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 
-	"katastr-p6/backend/internal/cache"
 	"katastr-p6/backend/internal/cuzk"
 )
 
@@ -19,10 +17,10 @@ type BuildingHandler struct {
 }
 
 // NewBuildingHandler creates a new BuildingHandler.
-func NewBuildingHandler(client *cuzk.Client, c *cache.RedisCache) *BuildingHandler {
+func NewBuildingHandler(client *cuzk.Client, ch *CachedHandler) *BuildingHandler {
 	return &BuildingHandler{
 		client: client,
-		ch:     NewCachedHandler(c),
+		ch:     ch,
 	}
 }
 
@@ -31,47 +29,65 @@ func (h *BuildingHandler) Search(w http.ResponseWriter, r *http.Request) {
 	areaStr := r.URL.Query().Get("area")
 	number := r.URL.Query().Get("number")
 
-	if areaStr == "" || number == "" {
-		http.Error(w, `{"error":"missing required parameters: area, number"}`, http.StatusBadRequest)
+	if missing := requiredParams(r, "area", "number"); len(missing) > 0 {
+		writeValidationError(w, r, missing...)
 		return
 	}
 
 	areaCode, err := strconv.Atoi(areaStr)
 	if err != nil {
-		http.Error(w, `{"error":"invalid area parameter"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "area", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("buildings:search", areaCode, number)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 1*time.Minute, func() (any, error) {
-		return h.client.SearchBuildings(r.Context(), areaCode, number)
+	key := CacheKey(prefixBuildingSearch, areaCode, number)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixBuildingSearch), func(ctx context.Context) (any, error) {
+		return h.client.SearchBuildings(ctx, areaCode, number)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
 }
 
 // Get handles GET /api/buildings/{id}
 func (h *BuildingHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("building", id)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 5*time.Minute, func() (any, error) {
-		return h.client.GetBuilding(r.Context(), id)
+	key := CacheKey(prefixBuilding, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixBuilding), func(ctx context.Context) (any, error) {
+		return withBuildingLinks(h.client.GetBuilding(ctx, id))
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
+}
+
+// Rights handles GET /api/buildings/{id}/rights
+func (h *BuildingHandler) Rights(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return
+	}
+
+	key := CacheKey(prefixBuildingRights, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixBuildingRights), func(ctx context.Context) (any, error) {
+		return h.client.GetBuildingRights(ctx, id)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	writeCached(w, data, freshness)
 }
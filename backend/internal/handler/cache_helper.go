@@ -2,51 +2,317 @@ package handler
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/cuzk"
 )
 
+// Freshness tells the client how current a cached response is.
+// It is sent in the X-Cache-Status response header.
+type Freshness string
+
+const (
+	Fresh        Freshness = "fresh"        // within soft TTL or just fetched
+	Revalidating Freshness = "revalidating" // past soft TTL, background refresh started
+	Stale        Freshness = "stale"        // past soft TTL, upstream is failing
+	Local        Freshness = "local"        // upstream is failing and nothing is cached; from an imported VFK extract
+)
+
+// worseFreshness returns the less current of a and b, for responses assembled
+// from several cache entries.
+func worseFreshness(a, b Freshness) Freshness {
+	rank := func(f Freshness) int {
+		switch f {
+		case Local:
+			return 3
+		case Stale:
+			return 2
+		case Revalidating:
+			return 1
+		}
+		return 0
+	}
+	if rank(b) > rank(a) {
+		return b
+	}
+	return a
+}
+
+// CacheStatusHeader carries the Freshness of a response.
+const CacheStatusHeader = "X-Cache-Status"
+
+// revalidateBackoff is how long a stale entry is served without new refresh
+// attempts after a refresh failed.
+const revalidateBackoff = 30 * time.Second
+
+// Cache key prefixes; they double as keys of config.CacheTTLPolicy.
+const (
+	prefixParcel                 = "parcel"
+	prefixParcelSearch           = "parcels:search"
+	prefixParcelPolygon          = "parcels:polygon"
+	prefixParcelNeighbors        = "parcels:neighbors"
+	prefixParcelRights           = "parcels:rights"
+	prefixParcelProceedings      = "parcels:proceedings"
+	prefixBuilding               = "building"
+	prefixBuildingSearch         = "buildings:search"
+	prefixBuildingRights         = "buildings:rights"
+	prefixBuildingProceedings    = "buildings:proceedings"
+	prefixUnit                   = "unit"
+	prefixUnitSearch             = "units:search"
+	prefixUnitRights             = "units:rights"
+	prefixUnitProceedings        = "units:proceedings"
+	prefixProceeding             = "proceeding"
+	prefixProceedingSearch       = "proceedings:search"
+	prefixProceedingOperations   = "proceeding:operations"
+	prefixProceedingParticipants = "proceeding:participants"
+	prefixProceedingObjects      = "proceeding:objects"
+	prefixProceedingDocuments    = "proceeding:documents"
+	prefixOwnershipSheet         = "ownership-sheet"
+)
+
+// cacheEntry is the envelope stored in the cache.
+// Negative entries record a CUZK 404 (NotFound set, no Data) or an empty
+// search result (Empty set); they use the shorter negative TTL and are never
+// served stale.
+type cacheEntry struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	NotFound   *notFoundEntry  `json:"notFound,omitempty"`
+	Empty      bool            `json:"empty,omitempty"`
+	FreshUntil time.Time       `json:"freshUntil"`
+	ExpiresAt  time.Time       `json:"expiresAt"`
+	RetryAfter time.Time       `json:"retryAfter,omitzero"` // set after a failed refresh
+}
+
+// notFoundEntry keeps what is needed to reproduce the upstream 404.
+type notFoundEntry struct {
+	Path    string `json:"path"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *cacheEntry) negative() bool {
+	return e.NotFound != nil || e.Empty
+}
+
+// err rebuilds the upstream error of a cached 404.
+func (e *notFoundEntry) err() error {
+	return &cuzk.APIError{StatusCode: http.StatusNotFound, Path: e.Path, Code: e.Code, Message: e.Message}
+}
+
+// emptier is implemented by search responses so empty results can be negatively cached.
+type emptier interface {
+	IsEmpty() bool
+}
+
 // CachedHandler provides cache-through helper for API handlers.
+// One instance is shared by all handlers so concurrent misses for the same key
+// are coalesced into a single upstream call.
 type CachedHandler struct {
-	cache *cache.RedisCache
+	cache  cache.Store
+	ttls   config.CacheTTLPolicy
+	flight flightGroup
+
+	hits      atomic.Uint64
+	negative  atomic.Uint64
+	stale     atomic.Uint64
+	local     atomic.Uint64
+	misses    atomic.Uint64
+	upstream  atomic.Uint64
+	coalesced atomic.Uint64
+}
+
+// CacheStats are cumulative counters since startup.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	NegativeHits  uint64 `json:"negativeHits"` // cached 404s and empty results
+	StaleHits     uint64 `json:"staleHits"`    // served revalidating or stale
+	LocalServed   uint64 `json:"localServed"`  // misses answered from local data while CUZK failed
+	Misses        uint64 `json:"misses"`
+	UpstreamCalls uint64 `json:"upstreamCalls"`
+	Coalesced     uint64 `json:"coalesced"` // misses served by another request's upstream call
 }
 
 // NewCachedHandler creates a new CachedHandler. cache can be nil (no caching).
-func NewCachedHandler(c *cache.RedisCache) *CachedHandler {
-	return &CachedHandler{cache: c}
+func NewCachedHandler(c cache.Store, ttls config.CacheTTLPolicy) *CachedHandler {
+	return &CachedHandler{cache: c, ttls: ttls}
 }
 
-// CacheKey builds a deterministic cache key from a prefix and parameters.
+// TTL returns the configured TTL policy for a cache key prefix.
+func (ch *CachedHandler) TTL(prefix string) config.CacheTTL {
+	return ch.ttls.For(prefix)
+}
+
+// KeyNamespace prefixes every cache key written by CachedHandler.
+const KeyNamespace = "cuzk:"
+
+// CacheKey builds a readable cache key from a prefix and parameters,
+// e.g. "cuzk:parcel:729272101" or "cuzk:parcels:search:729272:1521".
+// Floats are rounded to whole meters, matching the precision sent to CUZK.
 func CacheKey(prefix string, params ...any) string {
-	h := sha256.New()
+	var b strings.Builder
+	b.WriteString(KeyNamespace)
+	b.WriteString(prefix)
 	for _, p := range params {
-		fmt.Fprintf(h, "%v:", p)
+		b.WriteByte(':')
+		switch v := p.(type) {
+		case float64:
+			b.WriteString(strconv.FormatFloat(v, 'f', 0, 64))
+		default:
+			b.WriteString(keyEscaper.Replace(fmt.Sprint(v)))
+		}
+	}
+	return b.String()
+}
+
+// keyEscaper keeps parameters from introducing extra ':' segments or glob characters.
+var keyEscaper = strings.NewReplacer("%", "%25", ":", "%3A", "*", "%2A", " ", "%20")
+
+// Stats returns the cache and coalescing counters.
+func (ch *CachedHandler) Stats() CacheStats {
+	return CacheStats{
+		Hits:          ch.hits.Load(),
+		NegativeHits:  ch.negative.Load(),
+		StaleHits:     ch.stale.Load(),
+		LocalServed:   ch.local.Load(),
+		Misses:        ch.misses.Load(),
+		UpstreamCalls: ch.upstream.Load(),
+		Coalesced:     ch.coalesced.Load(),
 	}
-	return fmt.Sprintf("cuzk:%s:%x", prefix, h.Sum(nil)[:8])
 }
 
 // GetOrFetch tries the cache first; on miss calls fallback, caches the result, and returns JSON bytes.
-func (ch *CachedHandler) GetOrFetch(ctx context.Context, key string, ttl time.Duration, fallback func() (any, error)) ([]byte, error) {
-	// Try cache
-	if ch.cache != nil {
-		cached, err := ch.cache.Get(ctx, key)
-		if err == nil {
-			return []byte(cached), nil
+// Entries past ttl.Soft are returned immediately while a background refresh runs.
+// CUZK 404s and empty search results are cached for ttl.Negative.
+// Concurrent misses for the same key share one fallback call and one cache write.
+// fallback receives a context that is not canceled when the calling request ends.
+// When fallback fails with a *cuzk.LocalDataError, a stale entry is still
+// preferred; on a miss the local copy is returned as Local and not cached.
+func (ch *CachedHandler) GetOrFetch(ctx context.Context, key string, ttl config.CacheTTL, fallback func(ctx context.Context) (any, error)) ([]byte, Freshness, error) {
+	if entry, ok := ch.lookup(ctx, key); ok {
+		now := time.Now()
+		if entry.negative() {
+			ch.negative.Add(1)
+			if entry.NotFound != nil {
+				return nil, Fresh, entry.NotFound.err()
+			}
+			return entry.Data, Fresh, nil
+		}
+		if now.Before(entry.FreshUntil) {
+			ch.hits.Add(1)
+			return entry.Data, Fresh, nil
 		}
+
+		ch.stale.Add(1)
+		if now.Before(entry.RetryAfter) {
+			return entry.Data, Stale, nil
+		}
+		// Nobody waits on the refresh, so it must not delay interactive lookups.
+		bg := cuzk.WithPriority(context.WithoutCancel(ctx), max(cuzk.PriorityFrom(ctx), cuzk.PriorityBackground))
+		go ch.revalidate(bg, key, ttl, entry, fallback)
+		return entry.Data, Revalidating, nil
+	}
+	ch.misses.Add(1)
+
+	data, err, shared := ch.flight.do(ctx, key, func() ([]byte, error) {
+		return ch.fetch(context.WithoutCancel(ctx), key, ttl, fallback)
+	})
+	if shared {
+		ch.coalesced.Add(1)
+	}
+	if local, ok := cuzk.AsLocalData(err); ok {
+		if data, err := json.Marshal(local); err == nil {
+			ch.local.Add(1)
+			return data, Local, nil
+		}
+	}
+	return data, Fresh, err
+}
+
+// Warm makes sure key holds a fresh entry, fetching synchronously when it is
+// missing or stale, and returns the cached JSON. Used by the cache warmer.
+// Local data is no substitute for a CUZK answer here: its error is returned.
+func (ch *CachedHandler) Warm(ctx context.Context, key string, ttl config.CacheTTL, fallback func(ctx context.Context) (any, error)) ([]byte, error) {
+	if entry, ok := ch.lookup(ctx, key); ok && time.Now().Before(entry.FreshUntil) {
+		if entry.NotFound != nil {
+			return nil, entry.NotFound.err()
+		}
+		return entry.Data, nil
+	}
+	data, err, _ := ch.flight.do(ctx, key, func() ([]byte, error) {
+		return ch.fetch(ctx, key, ttl, fallback)
+	})
+	return data, err
+}
+
+// lookup returns the cached entry for key, if present, decodable and not past
+// ExpiresAt. A cache tier may hold an entry slightly longer than its TTL, so
+// negative entries in particular must not be trusted on the store's word.
+func (ch *CachedHandler) lookup(ctx context.Context, key string) (*cacheEntry, bool) {
+	if ch.cache == nil {
+		return nil, false
+	}
+	cached, err := ch.cache.Get(ctx, key)
+	if err != nil {
 		// Log non-miss errors but continue without cache.
-		if cached == "" && err.Error() != "redis: nil" {
+		if !errors.Is(err, cache.ErrMiss) {
 			slog.Warn("cache get error", "key", key, "error", err)
 		}
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal([]byte(cached), &entry); err != nil || (entry.Data == nil && entry.NotFound == nil) {
+		slog.Warn("cache entry undecodable, ignoring", "key", key, "error", err)
+		return nil, false
+	}
+	if !time.Now().Before(entry.ExpiresAt) {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// revalidate refreshes a stale entry. On failure the stale entry is kept until
+// its hard expiry and refreshes pause for revalidateBackoff.
+func (ch *CachedHandler) revalidate(ctx context.Context, key string, ttl config.CacheTTL, stale *cacheEntry, fallback func(ctx context.Context) (any, error)) {
+	_, err, shared := ch.flight.do(ctx, key, func() ([]byte, error) {
+		return ch.fetch(ctx, key, ttl, fallback)
+	})
+	if err == nil || shared || cuzk.IsNotFound(err) {
+		return
 	}
 
-	// Call the upstream API
-	data, err := fallback()
+	slog.Warn("cache revalidation failed, serving stale", "key", key, "error", err)
+	remaining := time.Until(stale.ExpiresAt)
+	if remaining <= 0 {
+		return
+	}
+	stale.RetryAfter = time.Now().Add(revalidateBackoff)
+	ch.store(ctx, key, stale, remaining)
+}
+
+// fetch calls the upstream API and stores the result in the cache.
+func (ch *CachedHandler) fetch(ctx context.Context, key string, ttl config.CacheTTL, fallback func(ctx context.Context) (any, error)) ([]byte, error) {
+	ch.upstream.Add(1)
+	now := time.Now()
+
+	data, err := fallback(ctx)
 	if err != nil {
+		if apiErr, ok := cuzk.AsAPIError(err); ok && cuzk.IsNotFound(err) && ttl.Negative > 0 {
+			ch.store(ctx, key, &cacheEntry{
+				NotFound:   &notFoundEntry{Path: apiErr.Path, Code: apiErr.Code, Message: apiErr.Message},
+				FreshUntil: now.Add(ttl.Negative),
+				ExpiresAt:  now.Add(ttl.Negative),
+			}, ttl.Negative)
+		}
 		return nil, err
 	}
 
@@ -55,12 +321,45 @@ func (ch *CachedHandler) GetOrFetch(ctx context.Context, key string, ttl time.Du
 		return nil, fmt.Errorf("json marshal: %w", err)
 	}
 
-	// Store in cache (best effort)
-	if ch.cache != nil {
-		if setErr := ch.cache.Set(ctx, key, string(jsonData), ttl); setErr != nil {
-			slog.Warn("cache set error", "key", key, "error", setErr)
+	if e, ok := data.(emptier); ok && e.IsEmpty() {
+		if ttl.Negative > 0 {
+			ch.store(ctx, key, &cacheEntry{
+				Data:       jsonData,
+				Empty:      true,
+				FreshUntil: now.Add(ttl.Negative),
+				ExpiresAt:  now.Add(ttl.Negative),
+			}, ttl.Negative)
 		}
+		return jsonData, nil
 	}
 
+	ch.store(ctx, key, &cacheEntry{
+		Data:       jsonData,
+		FreshUntil: now.Add(ttl.Soft),
+		ExpiresAt:  now.Add(ttl.Hard),
+	}, ttl.Hard)
+
 	return jsonData, nil
 }
+
+// store writes entry to the cache (best effort).
+func (ch *CachedHandler) store(ctx context.Context, key string, entry *cacheEntry, ttl time.Duration) {
+	if ch.cache == nil {
+		return
+	}
+	value, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("cache encode error", "key", key, "error", err)
+		return
+	}
+	if err := ch.cache.Set(ctx, key, string(value), ttl); err != nil {
+		slog.Warn("cache set error", "key", key, "error", err)
+	}
+}
+
+// writeCached writes a JSON payload returned by GetOrFetch.
+func writeCached(w http.ResponseWriter, data []byte, freshness Freshness) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(CacheStatusHeader, string(freshness))
+	w.Write(data)
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/cuzk"
+)
+
+func TestGetOrFetchCoalescesConcurrentMisses(t *testing.T) {
+	ch := NewCachedHandler(cache.NewMemoryCache(100, 0), config.DefaultCacheTTLs())
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	fallback := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return map[string]int{"id": 1}, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, _, err := ch.GetOrFetch(context.Background(), "cuzk:parcel:1", ch.TTL(prefixParcel), fallback)
+			if err != nil || string(data) != `{"id":1}` {
+				t.Errorf("GetOrFetch = %s, %v", data, err)
+			}
+		}()
+	}
+
+	for ch.misses.Load() < n && ch.hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	stats := ch.Stats()
+	if calls.Load() != 1 || stats.UpstreamCalls != 1 {
+		t.Errorf("expected 1 upstream call, got %d (stats %+v)", calls.Load(), stats)
+	}
+	if stats.Coalesced+stats.Hits != n-1 {
+		t.Errorf("expected %d coalesced or cached requests, got %+v", n-1, stats)
+	}
+}
+
+func TestGetOrFetchServesStaleWhileRevalidating(t *testing.T) {
+	ch := NewCachedHandler(cache.NewMemoryCache(100, 0), config.DefaultCacheTTLs())
+	ctx := context.Background()
+	ttl := config.CacheTTL{Soft: 10 * time.Millisecond, Hard: time.Minute}
+
+	var version atomic.Int32
+	failing := atomic.Bool{}
+	fallback := func(ctx context.Context) (any, error) {
+		if failing.Load() {
+			return nil, errors.New("upstream down")
+		}
+		return version.Add(1), nil
+	}
+
+	if data, f, err := ch.GetOrFetch(ctx, "k", ttl, fallback); err != nil || string(data) != "1" || f != Fresh {
+		t.Fatalf("first fetch = %s, %s, %v", data, f, err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if data, f, _ := ch.GetOrFetch(ctx, "k", ttl, fallback); string(data) != "1" || f != Revalidating {
+		t.Fatalf("expected stale 1 while revalidating, got %s, %s", data, f)
+	}
+	waitFor(t, func() bool {
+		data, _, _ := ch.GetOrFetch(ctx, "k", ttl, fallback)
+		return string(data) != "1"
+	})
+
+	failing.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	ch.GetOrFetch(ctx, "k", ttl, fallback) // triggers a failing refresh
+	waitFor(t, func() bool {
+		data, f, err := ch.GetOrFetch(ctx, "k", ttl, fallback)
+		return err == nil && len(data) > 0 && f == Stale
+	})
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGetOrFetchCachesNotFoundAndEmpty(t *testing.T) {
+	ch := NewCachedHandler(cache.NewMemoryCache(100, 0), config.DefaultCacheTTLs())
+	ctx := context.Background()
+	var calls atomic.Int32
+
+	notFound := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		return nil, &cuzk.APIError{StatusCode: 404, Path: "/Parcely/1", Message: "Parcela 1 neexistuje"}
+	}
+	for range 3 {
+		_, _, err := ch.GetOrFetch(ctx, "cuzk:parcel:1", ch.TTL(prefixParcel), notFound)
+		if !cuzk.IsNotFound(err) {
+			t.Fatalf("expected cached 404, got %v", err)
+		}
+	}
+
+	empty := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		return &cuzk.ParcelSearchResponse{Parcels: []cuzk.Parcel{}}, nil
+	}
+	for range 3 {
+		data, _, err := ch.GetOrFetch(ctx, "cuzk:parcels:search:729272:9999", ch.TTL(prefixParcelSearch), empty)
+		if err != nil || string(data) != `{"parcely":[],"total":0}` {
+			t.Fatalf("expected cached empty result, got %s, %v", data, err)
+		}
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", calls.Load())
+	}
+	if got := ch.Stats().NegativeHits; got != 4 {
+		t.Errorf("expected 4 negative hits, got %d", got)
+	}
+}
+
+func TestGetOrFetchIgnoresExpiredNegativeEntry(t *testing.T) {
+	store := cache.NewMemoryCache(100, 0)
+	ch := NewCachedHandler(store, config.DefaultCacheTTLs())
+	ctx := context.Background()
+
+	// An L1 refill can outlive the entry's own expiry.
+	past := time.Now().Add(-time.Second)
+	ch.store(ctx, "cuzk:proceeding:1", &cacheEntry{
+		NotFound:   &notFoundEntry{Path: "/Rizeni/1"},
+		FreshUntil: past,
+		ExpiresAt:  past,
+	}, time.Minute)
+
+	data, f, err := ch.GetOrFetch(ctx, "cuzk:proceeding:1", ch.TTL(prefixProceeding), func(ctx context.Context) (any, error) {
+		return map[string]int{"id": 1}, nil
+	})
+	if err != nil || string(data) != `{"id":1}` || f != Fresh {
+		t.Errorf("expected the expired 404 to be refetched, got %s, %s, %v", data, f, err)
+	}
+}
+
+func TestGetOrFetchPrefersStaleEntryToLocalData(t *testing.T) {
+	ch := NewCachedHandler(cache.NewMemoryCache(100, 0), config.DefaultCacheTTLs())
+	ctx := context.Background()
+	ttl := config.CacheTTL{Soft: 10 * time.Millisecond, Hard: time.Minute}
+
+	outage := atomic.Bool{}
+	fallback := func(ctx context.Context) (any, error) {
+		if outage.Load() {
+			return nil, &cuzk.LocalDataError{Object: "extract", Err: &cuzk.APIError{StatusCode: 503}}
+		}
+		return "cuzk", nil
+	}
+
+	// Nothing cached: the local copy is served as such and not stored.
+	outage.Store(true)
+	if data, f, err := ch.GetOrFetch(ctx, "miss", ttl, fallback); err != nil || string(data) != `"extract"` || f != Local {
+		t.Fatalf("miss during outage = %s, %s, %v", data, f, err)
+	}
+	if _, ok := ch.lookup(ctx, "miss"); ok {
+		t.Error("local data was cached")
+	}
+
+	// A stale CUZK entry survives a refresh that only finds local data.
+	outage.Store(false)
+	ch.GetOrFetch(ctx, "k", ttl, fallback)
+	outage.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	if data, f, _ := ch.GetOrFetch(ctx, "k", ttl, fallback); string(data) != `"cuzk"` || f != Revalidating {
+		t.Fatalf("expected stale CUZK data while revalidating, got %s, %s", data, f)
+	}
+	waitFor(t, func() bool {
+		data, f, err := ch.GetOrFetch(ctx, "k", ttl, fallback)
+		return err == nil && string(data) == `"cuzk"` && f == Stale
+	})
+	if s := ch.Stats(); s.LocalServed != 1 {
+		t.Errorf("LocalServed = %d, want 1", s.LocalServed)
+	}
+}
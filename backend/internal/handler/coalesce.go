@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent calls for the same key so that
+// identical cache misses share one upstream fetch.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done chan struct{}
+	val  []byte
+	err  error
+}
+
+// do runs fn once per key at a time. Callers arriving while fn is running wait
+// for its result instead of starting their own; shared reports whether this
+// caller joined an existing flight. A waiting caller gives up when ctx ends,
+// but the flight itself keeps running for the others.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() ([]byte, error)) (val []byte, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.val, f.err, true
+		case <-ctx.Done():
+			return nil, ctx.Err(), true
+		}
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	f.val, f.err = fn()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(f.done)
+
+	return f.val, f.err, false
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/middleware"
+)
+
+// Error codes used in ErrorBody.Code.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeNotFound             = "not_found"
+	CodeUpstreamUnauthorized = "upstream_unauthorized"
+	CodeRateLimited          = "rate_limited"
+	CodeUpstreamTimeout      = "upstream_timeout"
+	CodeUpstreamUnavailable  = "upstream_unavailable"
+	CodeQuotaExhausted       = "quota_exhausted"
+	CodeUpstreamError        = "upstream_error"
+	CodeInternal             = "internal_error"
+)
+
+// ErrorResponse is the JSON envelope for every API error:
+//
+//	{
+//	  "error": {
+//	    "code": "not_found",
+//	    "message": "Parcela 123 neexistuje",
+//	    "requestId": "9f86d081884c7d65",
+//	    "upstreamStatus": 404,
+//	    "details": [{"field": "area", "message": "must be an integer"}]
+//	  }
+//	}
+//
+// requestId matches the X-Request-ID response header; upstreamStatus is set
+// only when CUZK answered; details is set only for validation errors.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody is the payload of ErrorResponse.
+type ErrorBody struct {
+	Code           string       `json:"code"`
+	Message        string       `json:"message"`
+	RequestID      string       `json:"requestId,omitempty"`
+	UpstreamStatus int          `json:"upstreamStatus,omitempty"`
+	Details        []FieldError `json:"details,omitempty"`
+}
+
+// FieldError describes one invalid request parameter.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// writeError writes the error envelope with the given status.
+func writeError(w http.ResponseWriter, r *http.Request, status int, body ErrorBody) {
+	body.RequestID = middleware.GetRequestID(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: body})
+}
+
+// writeValidationError writes a 400 listing the invalid parameters.
+func writeValidationError(w http.ResponseWriter, r *http.Request, details ...FieldError) {
+	writeError(w, r, http.StatusBadRequest, ErrorBody{
+		Code:    CodeInvalidRequest,
+		Message: "invalid request parameters",
+		Details: details,
+	})
+}
+
+// requiredParams returns a FieldError for each empty query parameter in names.
+func requiredParams(r *http.Request, names ...string) []FieldError {
+	var missing []FieldError
+	for _, name := range names {
+		if r.URL.Query().Get(name) == "" {
+			missing = append(missing, FieldError{Field: name, Message: "required"})
+		}
+	}
+	return missing
+}
+
+// upstreamStatus maps an error from the CUZK client to the HTTP status and code returned to the app.
+func upstreamStatus(err error) (int, string) {
+	switch {
+	case cuzk.IsNotFound(err):
+		return http.StatusNotFound, CodeNotFound
+	case cuzk.IsUnauthorized(err):
+		return http.StatusUnauthorized, CodeUpstreamUnauthorized
+	case errors.Is(err, cuzk.ErrQuotaExhausted):
+		return http.StatusServiceUnavailable, CodeQuotaExhausted
+	case cuzk.IsCircuitOpen(err):
+		return http.StatusServiceUnavailable, CodeUpstreamUnavailable
+	case cuzk.IsRateLimited(err):
+		return http.StatusTooManyRequests, CodeRateLimited
+	case cuzk.IsTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, CodeUpstreamTimeout
+	}
+	if _, ok := cuzk.AsAPIError(err); ok {
+		return http.StatusBadGateway, CodeUpstreamError
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// writeUpstreamError writes err with the status matching the upstream failure.
+func writeUpstreamError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := upstreamStatus(err)
+	if status >= http.StatusInternalServerError {
+		slog.Error("upstream request failed", "path", r.URL.Path, "status", status, "error", err)
+	}
+
+	body := ErrorBody{Code: code, Message: http.StatusText(status)}
+	var openErr *cuzk.CircuitOpenError
+	if errors.As(err, &openErr) {
+		body.Message = "CUZK is unavailable, try again later"
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(time.Until(openErr.RetryAt).Seconds()+0.5))))
+	}
+	if apiErr, ok := cuzk.AsAPIError(err); ok {
+		body.UpstreamStatus = apiErr.StatusCode
+		if apiErr.Message != "" {
+			body.Message = apiErr.Message
+		}
+	}
+	writeError(w, r, status, body)
+}
+
+// NotFound answers unknown routes with the error envelope.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, ErrorBody{Code: CodeNotFound, Message: "no such endpoint"})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/middleware"
+)
+
+func TestWriteUpstreamErrorEnvelope(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{&cuzk.APIError{StatusCode: 404, Message: `Parcela "123" neexistuje`}, http.StatusNotFound, CodeNotFound},
+		{&cuzk.APIError{StatusCode: 401}, http.StatusUnauthorized, CodeUpstreamUnauthorized},
+		{&cuzk.APIError{StatusCode: 429}, http.StatusTooManyRequests, CodeRateLimited},
+		{&cuzk.APIError{StatusCode: 503, Retries: 2}, http.StatusBadGateway, CodeUpstreamError},
+		{&cuzk.APIError{StatusCode: 504}, http.StatusGatewayTimeout, CodeUpstreamTimeout},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/parcels/123", nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-1")
+
+		middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeUpstreamError(w, r, tt.err)
+		})).ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%v: status %d, want %d", tt.err, rec.Code, tt.wantStatus)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%v: content type %q", tt.err, ct)
+		}
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%v: invalid JSON %q: %v", tt.err, rec.Body.String(), err)
+		}
+		if resp.Error.Code != tt.wantCode || resp.Error.RequestID != "req-1" {
+			t.Errorf("%v: unexpected body %+v", tt.err, resp.Error)
+		}
+		if resp.Error.UpstreamStatus != tt.err.(*cuzk.APIError).StatusCode {
+			t.Errorf("%v: upstreamStatus %d", tt.err, resp.Error.UpstreamStatus)
+		}
+	}
+}
+
+func TestValidationErrorDetails(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/parcels/search?area=729272", nil)
+
+	writeValidationError(rec, req, requiredParams(req, "area", "number")...)
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || len(resp.Error.Details) != 1 || resp.Error.Details[0].Field != "number" {
+		t.Errorf("unexpected response %d %+v", rec.Code, resp.Error)
+	}
+}
@@ -7,31 +7,43 @@ import (
 	"time"
 
 	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/cuzk"
 )
 
 type HealthHandler struct {
-	cache *cache.RedisCache
+	cache   cache.Store
+	backend string
+	ch      *CachedHandler
+	client  *cuzk.Client
 }
 
-func NewHealthHandler(c *cache.RedisCache) *HealthHandler {
-	return &HealthHandler{cache: c}
+// NewHealthHandler creates a HealthHandler. backend is the configured cache backend name;
+// ch provides the cache and coalescing counters, client the upstream limiter state.
+func NewHealthHandler(c cache.Store, backend string, ch *CachedHandler, client *cuzk.Client) *HealthHandler {
+	return &HealthHandler{cache: c, backend: backend, ch: ch, client: client}
 }
 
 func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
-	redisStatus := "connected"
+	cacheStatus := "connected"
 	if h.cache != nil {
 		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
 		defer cancel()
 		if err := h.cache.Ping(ctx); err != nil {
-			redisStatus = "disconnected"
+			cacheStatus = "disconnected"
 		}
 	} else {
-		redisStatus = "not configured"
+		cacheStatus = "not configured"
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status": "ok",
-		"redis":  redisStatus,
+	json.NewEncoder(w).Encode(map[string]any{
+		"status":      "ok",
+		"cache":       h.backend,
+		"cacheStatus": cacheStatus,
+		"cacheStats":  h.ch.Stats(),
+		"upstream": map[string]any{
+			"limiter": h.client.LimiterStats(),
+			"breaker": h.client.BreakerStats(),
+		},
 	})
 }
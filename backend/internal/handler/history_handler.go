@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/history"
+)
+
+// HistoryHandler exposes the recorded versions of parcels, buildings and units.
+type HistoryHandler struct {
+	store history.Store
+}
+
+// NewHistoryHandler creates a new HistoryHandler.
+func NewHistoryHandler(store history.Store) *HistoryHandler {
+	return &HistoryHandler{store: store}
+}
+
+// versionEntry is a version in a history response with the fields changed since the previous one.
+type versionEntry struct {
+	history.Version
+	Changes []history.FieldChange `json:"changes"`
+}
+
+// versionRef identifies a version in a diff response.
+type versionRef struct {
+	Version int       `json:"version"`
+	SeenAt  time.Time `json:"seenAt"`
+}
+
+// ParcelHistory handles GET /api/parcels/{id}/history
+func (h *HistoryHandler) ParcelHistory(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, cuzk.ObjectParcel)
+}
+
+// ParcelDiff handles GET /api/parcels/{id}/diff?from={version|time}&to={version|time}
+func (h *HistoryHandler) ParcelDiff(w http.ResponseWriter, r *http.Request) {
+	h.diff(w, r, cuzk.ObjectParcel)
+}
+
+// BuildingHistory handles GET /api/buildings/{id}/history
+func (h *HistoryHandler) BuildingHistory(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, cuzk.ObjectBuilding)
+}
+
+// BuildingDiff handles GET /api/buildings/{id}/diff?from=&to=
+func (h *HistoryHandler) BuildingDiff(w http.ResponseWriter, r *http.Request) {
+	h.diff(w, r, cuzk.ObjectBuilding)
+}
+
+// UnitHistory handles GET /api/units/{id}/history
+func (h *HistoryHandler) UnitHistory(w http.ResponseWriter, r *http.Request) {
+	h.history(w, r, cuzk.ObjectUnit)
+}
+
+// UnitDiff handles GET /api/units/{id}/diff?from=&to=
+func (h *HistoryHandler) UnitDiff(w http.ResponseWriter, r *http.Request) {
+	h.diff(w, r, cuzk.ObjectUnit)
+}
+
+// history lists every recorded version, oldest first.
+func (h *HistoryHandler) history(w http.ResponseWriter, r *http.Request, kind string) {
+	id, versions, ok := h.versions(w, r, kind)
+	if !ok {
+		return
+	}
+	entries := make([]versionEntry, len(versions))
+	for i, v := range versions {
+		entries[i] = versionEntry{Version: v, Changes: []history.FieldChange{}}
+		if i > 0 {
+			entries[i].Changes = history.Diff(versions[i-1], v)
+		}
+	}
+	writeJSON(w, map[string]any{"kind": kind, "objectId": id, "versions": entries})
+}
+
+// diff compares two versions. to defaults to the latest version and from to
+// the version before to.
+func (h *HistoryHandler) diff(w http.ResponseWriter, r *http.Request, kind string) {
+	id, versions, ok := h.versions(w, r, kind)
+	if !ok {
+		return
+	}
+
+	var errs []FieldError
+	to := versions[len(versions)-1]
+	if s := r.URL.Query().Get("to"); s != "" {
+		v, err := history.Select(versions, s)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "to", Message: err.Error()})
+		}
+		to = v
+	}
+	from := versions[max(to.Version-2, 0)]
+	if s := r.URL.Query().Get("from"); s != "" {
+		v, err := history.Select(versions, s)
+		if err != nil {
+			errs = append(errs, FieldError{Field: "from", Message: err.Error()})
+		}
+		from = v
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"kind":     kind,
+		"objectId": id,
+		"from":     versionRef{Version: from.Version, SeenAt: from.SeenAt},
+		"to":       versionRef{Version: to.Version, SeenAt: to.SeenAt},
+		"changes":  history.Diff(from, to),
+	})
+}
+
+// versions loads the versions of the object in the {id} URL parameter,
+// writing an error response when there are none.
+func (h *HistoryHandler) versions(w http.ResponseWriter, r *http.Request, kind string) (int64, []history.Version, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return 0, nil, false
+	}
+	versions, err := h.store.Versions(kind, id)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+		return 0, nil, false
+	}
+	if len(versions) == 0 {
+		writeError(w, r, http.StatusNotFound, ErrorBody{
+			Code:    CodeNotFound,
+			Message: fmt.Sprintf("no history recorded for %s %d", kind, id),
+		})
+		return 0, nil, false
+	}
+	return id, versions, true
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// Links are the "_links" of a response: API paths of related resources keyed by relation.
+type Links map[string]string
+
+// ownershipSheetLinks links an object to its ownership sheet, or returns nil when the LV is unknown.
+func ownershipSheetLinks(area *cuzk.CadastralArea, lv *string) Links {
+	if area == nil || lv == nil || *lv == "" {
+		return nil
+	}
+	return Links{"ownershipSheet": fmt.Sprintf("/api/ownership-sheets/%d/%s", area.Code, *lv)}
+}
+
+// parcelResponse is a parcel detail with links to related resources.
+type parcelResponse struct {
+	*cuzk.Parcel
+	Links Links `json:"_links,omitempty"`
+}
+
+type buildingResponse struct {
+	*cuzk.Building
+	Links Links `json:"_links,omitempty"`
+}
+
+type unitResponse struct {
+	*cuzk.Unit
+	Links Links `json:"_links,omitempty"`
+}
+
+// withParcelLinks wraps the result of cuzk.Client.GetParcel for GetOrFetch.
+// A local copy carried by a *cuzk.LocalDataError is wrapped the same way.
+func withParcelLinks(p *cuzk.Parcel, err error) (any, error) {
+	if err != nil {
+		return nil, withLocalLinks(err, parcelLinks)
+	}
+	return parcelLinks(p), nil
+}
+
+func withBuildingLinks(b *cuzk.Building, err error) (any, error) {
+	if err != nil {
+		return nil, withLocalLinks(err, buildingLinks)
+	}
+	return buildingLinks(b), nil
+}
+
+func withUnitLinks(u *cuzk.Unit, err error) (any, error) {
+	if err != nil {
+		return nil, withLocalLinks(err, unitLinks)
+	}
+	return unitLinks(u), nil
+}
+
+func parcelLinks(p *cuzk.Parcel) any {
+	return parcelResponse{Parcel: p, Links: ownershipSheetLinks(&p.CadastralArea, p.OwnershipSheet)}
+}
+
+func buildingLinks(b *cuzk.Building) any {
+	return buildingResponse{Building: b, Links: ownershipSheetLinks(&b.CadastralArea, b.OwnershipSheet)}
+}
+
+func unitLinks(u *cuzk.Unit) any {
+	return unitResponse{Unit: u, Links: ownershipSheetLinks(u.CadastralArea, u.OwnershipSheet)}
+}
+
+// withLocalLinks applies wrap to the local copy err carries, if it is a T.
+func withLocalLinks[T any](err error, wrap func(T) any) error {
+	var localErr *cuzk.LocalDataError
+	if !errors.As(err, &localErr) {
+		return err
+	}
+	if v, ok := localErr.Object.(T); ok {
+		return &cuzk.LocalDataError{Object: wrap(v), Err: localErr.Err}
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// OwnershipSheetHandler handles ownership sheet (list vlastnictví) endpoints.
+type OwnershipSheetHandler struct {
+	client *cuzk.Client
+	ch     *CachedHandler
+}
+
+// NewOwnershipSheetHandler creates a new OwnershipSheetHandler.
+func NewOwnershipSheetHandler(client *cuzk.Client, ch *CachedHandler) *OwnershipSheetHandler {
+	return &OwnershipSheetHandler{
+		client: client,
+		ch:     ch,
+	}
+}
+
+// Get handles GET /api/ownership-sheets/{area}/{number}
+func (h *OwnershipSheetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	areaCode, err := strconv.Atoi(chi.URLParam(r, "area"))
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "area", Message: "must be an integer"})
+		return
+	}
+	number, err := strconv.Atoi(chi.URLParam(r, "number"))
+	if err != nil || number <= 0 {
+		writeValidationError(w, r, FieldError{Field: "number", Message: "must be a positive integer"})
+		return
+	}
+
+	key := CacheKey(prefixOwnershipSheet, areaCode, number)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixOwnershipSheet), func(ctx context.Context) (any, error) {
+		return h.client.GetOwnershipSheet(ctx, areaCode, number)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	writeCached(w, data, freshness)
+}
@@ -1,14 +1,14 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 
-	"katastr-p6/backend/internal/cache"
 	"katastr-p6/backend/internal/coords"
 	"katastr-p6/backend/internal/cuzk"
 )
@@ -20,61 +20,135 @@ type ParcelHandler struct {
 }
 
 // NewParcelHandler creates a new ParcelHandler.
-func NewParcelHandler(client *cuzk.Client, c *cache.RedisCache) *ParcelHandler {
+func NewParcelHandler(client *cuzk.Client, ch *CachedHandler) *ParcelHandler {
 	return &ParcelHandler{
 		client: client,
-		ch:     NewCachedHandler(c),
+		ch:     ch,
 	}
 }
 
-// Search handles GET /api/parcels/search?area={code}&number={num}
+// Search handles GET /api/parcels/search?area={code}&number={designation}
+// number is a full designation such as "st. 1234/5" or "1234/5"; alternatively
+// pass base={n}&subdivision={n}. type=building|land restricts the numbering type.
 func (h *ParcelHandler) Search(w http.ResponseWriter, r *http.Request) {
-	areaStr := r.URL.Query().Get("area")
-	number := r.URL.Query().Get("number")
-
-	if areaStr == "" || number == "" {
-		http.Error(w, `{"error":"missing required parameters: area, number"}`, http.StatusBadRequest)
+	if missing := requiredParams(r, "area"); len(missing) > 0 {
+		writeValidationError(w, r, missing...)
 		return
 	}
-
-	areaCode, err := strconv.Atoi(areaStr)
+	areaCode, err := strconv.Atoi(r.URL.Query().Get("area"))
 	if err != nil {
-		http.Error(w, `{"error":"invalid area parameter"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "area", Message: "must be an integer"})
+		return
+	}
+	number, errs := parcelNumberParams(r)
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
 		return
 	}
 
-	key := CacheKey("parcels:search", areaCode, number)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 1*time.Minute, func() (any, error) {
-		return h.client.SearchParcels(r.Context(), areaCode, number)
+	key := CacheKey(prefixParcelSearch, areaCode, number)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixParcelSearch), func(ctx context.Context) (any, error) {
+		return h.client.SearchParcels(ctx, areaCode, number)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
+}
+
+// numberingTypes maps the type query parameter to CUZK numbering types.
+var numberingTypes = map[string]string{
+	"building": cuzk.NumberingBuilding,
+	"land":     cuzk.NumberingLand,
+}
+
+// parcelNumberParams reads a parcel designation from either number or
+// base/subdivision, plus an optional type.
+func parcelNumberParams(r *http.Request) (cuzk.ParcelNumber, []FieldError) {
+	q := r.URL.Query()
+	var n cuzk.ParcelNumber
+
+	switch {
+	case q.Get("number") != "" && (q.Get("base") != "" || q.Get("subdivision") != ""):
+		return n, []FieldError{{Field: "number", Message: "use either number or base/subdivision"}}
+	case q.Get("number") != "":
+		parsed, err := cuzk.ParseParcelNumber(q.Get("number"))
+		if err != nil {
+			return n, []FieldError{{Field: "number", Message: "must be a parcel number like \"st. 1234/5\" or \"1234/5\""}}
+		}
+		n = parsed
+	case q.Get("base") != "":
+		base, err := strconv.Atoi(q.Get("base"))
+		if err != nil || base <= 0 {
+			return n, []FieldError{{Field: "base", Message: "must be a positive integer"}}
+		}
+		n.Base = base
+		if s := q.Get("subdivision"); s != "" {
+			sub, err := strconv.Atoi(s)
+			if err != nil || sub <= 0 {
+				return n, []FieldError{{Field: "subdivision", Message: "must be a positive integer"}}
+			}
+			n.Subdivision = sub
+		}
+	default:
+		return n, []FieldError{{Field: "number", Message: "required unless base is given"}}
+	}
+
+	if t := q.Get("type"); t != "" {
+		numbering, ok := numberingTypes[t]
+		if !ok {
+			return n, []FieldError{{Field: "type", Message: "must be building or land"}}
+		}
+		if n.Type != "" && n.Type != numbering {
+			return n, []FieldError{{Field: "type", Message: "conflicts with the prefix in number"}}
+		}
+		n.Type = numbering
+	}
+	return n, nil
 }
 
 // Get handles GET /api/parcels/{id}
 func (h *ParcelHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("parcel", id)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 5*time.Minute, func() (any, error) {
-		return h.client.GetParcel(r.Context(), id)
+	key := CacheKey(prefixParcel, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixParcel), func(ctx context.Context) (any, error) {
+		return withParcelLinks(h.client.GetParcel(ctx, id))
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
+}
+
+// Rights handles GET /api/parcels/{id}/rights
+// Lists mortgages, easements, pre-emption rights and other restrictions on the parcel;
+// rizeniIds resolve via GET /api/proceedings/{id}.
+func (h *ParcelHandler) Rights(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return
+	}
+
+	key := CacheKey(prefixParcelRights, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixParcelRights), func(ctx context.Context) (any, error) {
+		return h.client.GetParcelRights(ctx, id)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	writeCached(w, data, freshness)
 }
 
 // Polygon handles GET /api/parcels/polygon?lat={lat}&lon={lon}&radius={m}
@@ -83,19 +157,19 @@ func (h *ParcelHandler) Polygon(w http.ResponseWriter, r *http.Request) {
 	lonStr := r.URL.Query().Get("lon")
 	radiusStr := r.URL.Query().Get("radius")
 
-	if latStr == "" || lonStr == "" {
-		http.Error(w, `{"error":"missing required parameters: lat, lon"}`, http.StatusBadRequest)
+	if missing := requiredParams(r, "lat", "lon"); len(missing) > 0 {
+		writeValidationError(w, r, missing...)
 		return
 	}
 
 	lat, err := strconv.ParseFloat(latStr, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid lat"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "lat", Message: "must be a number"})
 		return
 	}
 	lon, err := strconv.ParseFloat(lonStr, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid lon"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "lon", Message: "must be a number"})
 		return
 	}
 
@@ -109,36 +183,66 @@ func (h *ParcelHandler) Polygon(w http.ResponseWriter, r *http.Request) {
 	// Convert WGS-84 to S-JTSK for the CUZK API.
 	x, y := coords.WGS84ToSJTSK(lat, lon)
 
-	key := CacheKey("parcels:polygon", x, y, radius)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 1*time.Minute, func() (any, error) {
-		return h.client.PolygonParcels(r.Context(), x, y, radius)
+	key := CacheKey(prefixParcelPolygon, x, y, radius)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixParcelPolygon), func(ctx context.Context) (any, error) {
+		return h.client.PolygonParcels(ctx, x, y, radius)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
 }
 
 // Neighbors handles GET /api/parcels/neighbors/{id}
 func (h *ParcelHandler) Neighbors(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("parcels:neighbors", id)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 5*time.Minute, func() (any, error) {
-		return h.client.NeighborParcels(r.Context(), id)
+	key := CacheKey(prefixParcelNeighbors, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixParcelNeighbors), func(ctx context.Context) (any, error) {
+		return h.client.NeighborParcels(ctx, id)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
+}
+
+// WarmSearch pre-populates the parcels:search entry for areaCode and base number
+// (any subdivision and numbering type) and returns the IDs of the parcels found.
+func (h *ParcelHandler) WarmSearch(ctx context.Context, areaCode int, base int) ([]int64, error) {
+	number := cuzk.ParcelNumber{Base: base}
+	key := CacheKey(prefixParcelSearch, areaCode, number)
+	data, err := h.ch.Warm(ctx, key, h.ch.TTL(prefixParcelSearch), func(ctx context.Context) (any, error) {
+		return h.client.SearchParcels(ctx, areaCode, number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp cuzk.ParcelSearchResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decode cached search: %w", err)
+	}
+	ids := make([]int64, 0, len(resp.Parcels))
+	for _, p := range resp.Parcels {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+// WarmParcel pre-populates the parcel detail entry.
+func (h *ParcelHandler) WarmParcel(ctx context.Context, id int64) error {
+	key := CacheKey(prefixParcel, id)
+	_, err := h.ch.Warm(ctx, key, h.ch.TTL(prefixParcel), func(ctx context.Context) (any, error) {
+		return withParcelLinks(h.client.GetParcel(ctx, id))
+	})
+	return err
 }
@@ -1,14 +1,18 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
-	"time"
+	"strings"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 
-	"katastr-p6/backend/internal/cache"
 	"katastr-p6/backend/internal/cuzk"
 )
 
@@ -19,30 +23,220 @@ type ProceedingHandler struct {
 }
 
 // NewProceedingHandler creates a new ProceedingHandler.
-func NewProceedingHandler(client *cuzk.Client, c *cache.RedisCache) *ProceedingHandler {
+func NewProceedingHandler(client *cuzk.Client, ch *CachedHandler) *ProceedingHandler {
 	return &ProceedingHandler{
 		client: client,
-		ch:     NewCachedHandler(c),
+		ch:     ch,
 	}
 }
 
-// Get handles GET /api/proceedings/{id}
+// proceedingParts are the expandable lists of a proceeding detail, keyed by
+// their "expand" name.
+var proceedingParts = map[string]struct {
+	prefix string
+	fetch  func(c *cuzk.Client, ctx context.Context, id int64) (any, error)
+}{
+	"operations": {prefixProceedingOperations, func(c *cuzk.Client, ctx context.Context, id int64) (any, error) {
+		return c.GetProceedingOperations(ctx, id)
+	}},
+	"participants": {prefixProceedingParticipants, func(c *cuzk.Client, ctx context.Context, id int64) (any, error) {
+		return c.GetProceedingParticipants(ctx, id)
+	}},
+	"objects": {prefixProceedingObjects, func(c *cuzk.Client, ctx context.Context, id int64) (any, error) {
+		return c.GetProceedingObjects(ctx, id)
+	}},
+	"documents": {prefixProceedingDocuments, func(c *cuzk.Client, ctx context.Context, id int64) (any, error) {
+		return c.GetProceedingDocuments(ctx, id)
+	}},
+}
+
+// Get handles GET /api/proceedings/{id}[?expand=operations,participants,objects,documents]
+//
+// Each expanded list is cached on its own: the operations timeline follows the
+// short proceeding TTL while participants, objects and documents rarely change.
 func (h *ProceedingHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return
+	}
+	expand, errs := expandParam(r)
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	key := CacheKey(prefixProceeding, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixProceeding), func(ctx context.Context) (any, error) {
+		return h.client.GetProceeding(ctx, id)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+	if len(expand) == 0 {
+		writeCached(w, data, freshness)
+		return
+	}
+
+	parts := make([][]byte, len(expand))
+	partFreshness := make([]Freshness, len(expand))
+	partErrs := make([]error, len(expand))
+	var wg sync.WaitGroup
+	for i, name := range expand {
+		part := proceedingParts[name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			parts[i], partFreshness[i], partErrs[i] = h.ch.GetOrFetch(r.Context(), CacheKey(part.prefix, id), h.ch.TTL(part.prefix), func(ctx context.Context) (any, error) {
+				return part.fetch(h.client, ctx, id)
+			})
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(partErrs...); err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	var p cuzk.Proceeding
+	if err := json.Unmarshal(data, &p); err != nil {
+		writeUpstreamError(w, r, fmt.Errorf("decode cached proceeding: %w", err))
+		return
+	}
+	for i, name := range expand {
+		var target any
+		switch name {
+		case "operations":
+			target = &p.Operations
+		case "participants":
+			target = &p.Participants
+		case "objects":
+			target = &p.Objects
+		case "documents":
+			target = &p.Documents
+		}
+		if err := json.Unmarshal(parts[i], target); err != nil {
+			writeUpstreamError(w, r, fmt.Errorf("decode cached proceeding %s: %w", name, err))
+			return
+		}
+		freshness = worseFreshness(freshness, partFreshness[i])
+	}
+	out, err := json.Marshal(p)
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+	writeCached(w, out, freshness)
+}
+
+// expandParam parses the comma-separated "expand" query parameter into
+// proceedingParts names, without duplicates.
+func expandParam(r *http.Request) ([]string, []FieldError) {
+	var names []string
+	for _, v := range r.URL.Query()["expand"] {
+		for name := range strings.SplitSeq(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || slices.Contains(names, name) {
+				continue
+			}
+			if _, ok := proceedingParts[name]; !ok {
+				return nil, []FieldError{{Field: "expand", Message: "must list operations, participants, objects or documents"}}
+			}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Search handles GET /api/proceedings/search?designation={V-1234/2025-101}
+// and GET /api/proceedings/search?year={yyyy}&office={code}[&type={V}][&number={n}]
+// for listing an office's proceedings of a year.
+func (h *ProceedingHandler) Search(w http.ResponseWriter, r *http.Request) {
+	d, errs := proceedingDesignationParams(r)
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	key := CacheKey(prefixProceedingSearch, d.Office, d.Year, d.Type, d.Sequence)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixProceedingSearch), func(ctx context.Context) (any, error) {
+		return h.client.SearchProceedings(ctx, d)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	writeCached(w, data, freshness)
+}
+
+// proceedingDesignationParams reads either a full designation or its year/office/type/number parts.
+func proceedingDesignationParams(r *http.Request) (cuzk.ProceedingDesignation, []FieldError) {
+	q := r.URL.Query()
+	if s := q.Get("designation"); s != "" {
+		d, err := cuzk.ParseProceedingDesignation(s)
+		if err != nil {
+			return d, []FieldError{{Field: "designation", Message: "must look like V-1234/2025-101"}}
+		}
+		return d, nil
+	}
+
+	if missing := requiredParams(r, "year", "office"); len(missing) > 0 {
+		return cuzk.ProceedingDesignation{}, missing
+	}
+	var d cuzk.ProceedingDesignation
+	var errs []FieldError
+	year, err := strconv.Atoi(q.Get("year"))
+	if err != nil || year < 1000 || year > 9999 {
+		errs = append(errs, FieldError{Field: "year", Message: "must be a four-digit year"})
+	}
+	d.Year = year
+	if d.Office = q.Get("office"); len(d.Office) != 3 || strings.Trim(d.Office, "0123456789") != "" {
+		errs = append(errs, FieldError{Field: "office", Message: "must be a three-digit office code"})
+	}
+	d.Type = strings.ToUpper(q.Get("type"))
+	if s := q.Get("number"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			errs = append(errs, FieldError{Field: "number", Message: "must be a positive integer"})
+		}
+		d.Sequence = n
+	}
+	return d, errs
+}
+
+// ForParcel handles GET /api/parcels/{id}/proceedings
+func (h *ProceedingHandler) ForParcel(w http.ResponseWriter, r *http.Request) {
+	h.forObject(w, r, prefixParcelProceedings, h.client.GetParcelProceedings)
+}
+
+// ForBuilding handles GET /api/buildings/{id}/proceedings
+func (h *ProceedingHandler) ForBuilding(w http.ResponseWriter, r *http.Request) {
+	h.forObject(w, r, prefixBuildingProceedings, h.client.GetBuildingProceedings)
+}
+
+// ForUnit handles GET /api/units/{id}/proceedings
+func (h *ProceedingHandler) ForUnit(w http.ResponseWriter, r *http.Request) {
+	h.forObject(w, r, prefixUnitProceedings, h.client.GetUnitProceedings)
+}
+
+// forObject lists the proceedings affecting the object identified by the {id} URL parameter.
+func (h *ProceedingHandler) forObject(w http.ResponseWriter, r *http.Request, prefix string, list func(context.Context, int64) (*cuzk.ProceedingSearchResponse, error)) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("proceeding", id)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 5*time.Minute, func() (any, error) {
-		return h.client.GetProceeding(r.Context(), id)
+	key := CacheKey(prefix, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefix), func(ctx context.Context) (any, error) {
+		return list(ctx, id)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
 }
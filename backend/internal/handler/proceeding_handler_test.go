@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/cache"
+	"katastr-p6/backend/internal/config"
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/cuzkmock"
+)
+
+func TestProceedingExpand(t *testing.T) {
+	_, ts := cuzkmock.NewTestServer("")
+	defer ts.Close()
+
+	ch := NewCachedHandler(cache.NewMemoryCache(100, 0), config.DefaultCacheTTLs())
+	h := NewProceedingHandler(cuzk.NewClient(ts.URL, "", cuzk.WithRateLimit(0, 1)), ch)
+	r := chi.NewRouter()
+	r.Get("/proceedings/{id}", h.Get)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proceedings/9101002?expand=operations,objects", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var p cuzk.Proceeding
+	json.Unmarshal(rec.Body.Bytes(), &p)
+	if len(p.Operations) != 2 || p.Operations[1].Type != "plomba" || len(p.Objects) != 2 {
+		t.Errorf("expected operations and objects, got %+v", p)
+	}
+	if p.Participants != nil || p.Documents != nil {
+		t.Errorf("expected participants and documents left out, got %+v", p)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/proceedings/9101002?expand=history", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for unknown expand, got %d", rec.Code)
+	}
+}
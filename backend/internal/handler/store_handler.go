@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"katastr-p6/backend/internal/store"
+)
+
+// StoreHandler exposes a hot backup of the embedded database.
+type StoreHandler struct {
+	db *store.DB
+}
+
+// NewStoreHandler creates a new StoreHandler.
+func NewStoreHandler(db *store.DB) *StoreHandler {
+	return &StoreHandler{db: db}
+}
+
+// Backup handles GET /api/admin/store/backup, streaming a consistent copy of
+// the database while the server keeps serving. Restore it with "server restore".
+func (h *StoreHandler) Backup(w http.ResponseWriter, r *http.Request) {
+	// The server write timeout is too short for a large database.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	name := fmt.Sprintf("katastr-%s.db", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	n, err := h.db.Backup(w)
+	if err != nil {
+		// Headers are gone once copying started; all we can do is log and cut the response.
+		slog.Error("store backup failed", "error", err, "written", n)
+		panic(http.ErrAbortHandler)
+	}
+	slog.Info("store backup served", "bytes", n)
+}
@@ -1,14 +1,12 @@
 package handler
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 
-	"katastr-p6/backend/internal/cache"
 	"katastr-p6/backend/internal/cuzk"
 )
 
@@ -19,10 +17,10 @@ type UnitHandler struct {
 }
 
 // NewUnitHandler creates a new UnitHandler.
-func NewUnitHandler(client *cuzk.Client, c *cache.RedisCache) *UnitHandler {
+func NewUnitHandler(client *cuzk.Client, ch *CachedHandler) *UnitHandler {
 	return &UnitHandler{
 		client: client,
-		ch:     NewCachedHandler(c),
+		ch:     ch,
 	}
 }
 
@@ -32,47 +30,65 @@ func (h *UnitHandler) Search(w http.ResponseWriter, r *http.Request) {
 	buildingNo := r.URL.Query().Get("buildingNo")
 	unitNo := r.URL.Query().Get("unitNo")
 
-	if areaStr == "" || buildingNo == "" || unitNo == "" {
-		http.Error(w, `{"error":"missing required parameters: area, buildingNo, unitNo"}`, http.StatusBadRequest)
+	if missing := requiredParams(r, "area", "buildingNo", "unitNo"); len(missing) > 0 {
+		writeValidationError(w, r, missing...)
 		return
 	}
 
 	areaCode, err := strconv.Atoi(areaStr)
 	if err != nil {
-		http.Error(w, `{"error":"invalid area parameter"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "area", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("units:search", areaCode, buildingNo, unitNo)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 1*time.Minute, func() (any, error) {
-		return h.client.SearchUnits(r.Context(), areaCode, buildingNo, unitNo)
+	key := CacheKey(prefixUnitSearch, areaCode, buildingNo, unitNo)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixUnitSearch), func(ctx context.Context) (any, error) {
+		return h.client.SearchUnits(ctx, areaCode, buildingNo, unitNo)
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
 }
 
 // Get handles GET /api/units/{id}
 func (h *UnitHandler) Get(w http.ResponseWriter, r *http.Request) {
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"invalid id"}`, http.StatusBadRequest)
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
 		return
 	}
 
-	key := CacheKey("unit", id)
-	data, err := h.ch.GetOrFetch(r.Context(), key, 5*time.Minute, func() (any, error) {
-		return h.client.GetUnit(r.Context(), id)
+	key := CacheKey(prefixUnit, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixUnit), func(ctx context.Context) (any, error) {
+		return withUnitLinks(h.client.GetUnit(ctx, id))
 	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf(`{"error":"%s"}`, err), http.StatusInternalServerError)
+		writeUpstreamError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	writeCached(w, data, freshness)
+}
+
+// Rights handles GET /api/units/{id}/rights
+func (h *UnitHandler) Rights(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeValidationError(w, r, FieldError{Field: "id", Message: "must be an integer"})
+		return
+	}
+
+	key := CacheKey(prefixUnitRights, id)
+	data, freshness, err := h.ch.GetOrFetch(r.Context(), key, h.ch.TTL(prefixUnitRights), func(ctx context.Context) (any, error) {
+		return h.client.GetUnitRights(ctx, id)
+	})
+	if err != nil {
+		writeUpstreamError(w, r, err)
+		return
+	}
+
+	writeCached(w, data, freshness)
 }
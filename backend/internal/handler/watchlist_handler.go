@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/watchlist"
+)
+
+// WatchlistHandler manages watched objects and exposes their change events.
+type WatchlistHandler struct {
+	store   watchlist.Store
+	monitor *watchlist.Monitor
+}
+
+// NewWatchlistHandler creates a new WatchlistHandler.
+func NewWatchlistHandler(store watchlist.Store, monitor *watchlist.Monitor) *WatchlistHandler {
+	return &WatchlistHandler{store: store, monitor: monitor}
+}
+
+// watchlistItemRequest is the body of PUT /api/watchlist/{kind}/{id}.
+type watchlistItemRequest struct {
+	Label string `json:"label"`
+	Note  string `json:"note"`
+}
+
+// List handles GET /api/watchlist
+func (h *WatchlistHandler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.store.Items()
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"items": items})
+}
+
+// Get handles GET /api/watchlist/{kind}/{id}
+func (h *WatchlistHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ref, ok := watchlistRef(w, r)
+	if !ok {
+		return
+	}
+	item, err := h.store.Item(ref)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, item)
+}
+
+// Put handles PUT /api/watchlist/{kind}/{id}: starts watching the object
+// (201) or updates its label and note (200). The first check only records a baseline.
+func (h *WatchlistHandler) Put(w http.ResponseWriter, r *http.Request) {
+	ref, ok := watchlistRef(w, r)
+	if !ok {
+		return
+	}
+	var req watchlistItemRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeValidationError(w, r, FieldError{Field: "body", Message: "must be a JSON object with label and note"})
+			return
+		}
+	}
+
+	err := h.store.UpdateItem(ref, func(it *watchlist.Item) {
+		it.Label, it.Note = req.Label, req.Note
+	})
+	status := http.StatusOK
+	if errors.Is(err, watchlist.ErrNotFound) {
+		status = http.StatusCreated
+		err = h.store.PutItem(watchlist.Item{Ref: ref, Label: req.Label, Note: req.Note, CreatedAt: time.Now()})
+	}
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+
+	item, err := h.store.Item(ref)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(item)
+		return
+	}
+	writeJSON(w, item)
+}
+
+// Delete handles DELETE /api/watchlist/{kind}/{id}
+func (h *WatchlistHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ref, ok := watchlistRef(w, r)
+	if !ok {
+		return
+	}
+	if err := h.store.DeleteItem(ref); err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Check handles POST /api/watchlist/{kind}/{id}/check: checks the object now
+// and returns the changes found.
+func (h *WatchlistHandler) Check(w http.ResponseWriter, r *http.Request) {
+	ref, ok := watchlistRef(w, r)
+	if !ok {
+		return
+	}
+	events, err := h.monitor.Check(r.Context(), ref)
+	switch {
+	case errors.Is(err, watchlist.ErrNotFound):
+		writeStoreError(w, r, err)
+		return
+	case err != nil:
+		writeUpstreamError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"events": nonNilEvents(events)})
+}
+
+// Events handles GET /api/watchlist/events?after={seq}&limit={n}
+// and GET /api/watchlist/{kind}/{id}/events for one object.
+func (h *WatchlistHandler) Events(w http.ResponseWriter, r *http.Request) {
+	var q watchlist.EventQuery
+	if chi.URLParam(r, "kind") != "" {
+		ref, ok := watchlistRef(w, r)
+		if !ok {
+			return
+		}
+		q.Ref = &ref
+	}
+
+	var errs []FieldError
+	if s := r.URL.Query().Get("after"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			errs = append(errs, FieldError{Field: "after", Message: "must be a non-negative integer"})
+		}
+		q.AfterSeq = n
+	}
+	q.Limit = 100
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			errs = append(errs, FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+		q.Limit = n
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	events, err := h.store.Events(q)
+	if err != nil {
+		writeStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"events": nonNilEvents(events)})
+}
+
+// watchlistRef reads the {kind} and {id} URL parameters, writing a 400 when invalid.
+func watchlistRef(w http.ResponseWriter, r *http.Request) (watchlist.Ref, bool) {
+	var errs []FieldError
+	kind, err := watchlist.ParseKind(chi.URLParam(r, "kind"))
+	if err != nil {
+		errs = append(errs, FieldError{Field: "kind", Message: "must be parcel, building or unit"})
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		errs = append(errs, FieldError{Field: "id", Message: "must be an integer"})
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return watchlist.Ref{}, false
+	}
+	return watchlist.Ref{Kind: kind, ObjectID: id}, true
+}
+
+// writeStoreError answers a watchlist storage failure, 404 for unwatched objects.
+func writeStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, watchlist.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, ErrorBody{Code: CodeNotFound, Message: "object is not on the watchlist"})
+		return
+	}
+	writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+}
+
+func nonNilEvents(events []watchlist.Event) []watchlist.Event {
+	if events == nil {
+		return []watchlist.Event{}
+	}
+	return events
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"katastr-p6/backend/internal/watchlist"
+	"katastr-p6/backend/internal/webhook"
+)
+
+// WebhookHandler manages webhook subscriptions and exposes their deliveries;
+// mounted under /api/admin.
+type WebhookHandler struct {
+	store      webhook.Store
+	dispatcher *webhook.Dispatcher
+}
+
+// NewWebhookHandler creates a new WebhookHandler.
+func NewWebhookHandler(store webhook.Store, dispatcher *webhook.Dispatcher) *WebhookHandler {
+	return &WebhookHandler{store: store, dispatcher: dispatcher}
+}
+
+// subscriptionRequest is the body of POST /api/admin/webhooks.
+type subscriptionRequest struct {
+	URL    string         `json:"url"`
+	Secret string         `json:"secret"` // generated when empty
+	Filter webhook.Filter `json:"filter"`
+}
+
+// Subscriptions handles GET /api/admin/webhooks
+func (h *WebhookHandler) Subscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.store.Subscriptions()
+	if err != nil {
+		writeWebhookStoreError(w, r, err)
+		return
+	}
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+	writeJSON(w, map[string]any{"subscriptions": subs})
+}
+
+// Subscribe handles POST /api/admin/webhooks. The response is the only place
+// the signing secret is shown.
+func (h *WebhookHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	var req subscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeValidationError(w, r, FieldError{Field: "body", Message: "must be a JSON object with url, secret and filter"})
+		return
+	}
+	if errs := validateSubscription(req); len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	sub := webhook.Subscription{
+		ID:        webhook.NewID(),
+		URL:       req.URL,
+		Secret:    req.Secret,
+		Filter:    req.Filter,
+		CreatedAt: time.Now(),
+	}
+	if sub.Secret == "" {
+		sub.Secret = webhook.NewSecret()
+	}
+	if err := h.store.PutSubscription(sub); err != nil {
+		writeWebhookStoreError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(sub)
+}
+
+// Unsubscribe handles DELETE /api/admin/webhooks/{id}; pending deliveries are dropped.
+func (h *WebhookHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.DeleteSubscription(chi.URLParam(r, "id")); err != nil {
+		writeWebhookStoreError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries handles GET /api/admin/webhooks/deliveries?status={pending|delivered|dead}&subscription={id}&limit={n}
+// status=dead lists the dead-letter queue.
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	q := webhook.DeliveryQuery{
+		Status:         r.URL.Query().Get("status"),
+		SubscriptionID: r.URL.Query().Get("subscription"),
+		Limit:          100,
+	}
+	var errs []FieldError
+	switch q.Status {
+	case "", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusDead:
+	default:
+		errs = append(errs, FieldError{Field: "status", Message: "must be pending, delivered or dead"})
+	}
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			errs = append(errs, FieldError{Field: "limit", Message: "must be a positive integer"})
+		}
+		q.Limit = n
+	}
+	if len(errs) > 0 {
+		writeValidationError(w, r, errs...)
+		return
+	}
+
+	deliveries, err := h.store.Deliveries(q)
+	if err != nil {
+		writeWebhookStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, map[string]any{"deliveries": deliveries})
+}
+
+// Delivery handles GET /api/admin/webhooks/deliveries/{id}
+func (h *WebhookHandler) Delivery(w http.ResponseWriter, r *http.Request) {
+	d, err := h.store.Delivery(chi.URLParam(r, "id"))
+	if err != nil {
+		writeWebhookStoreError(w, r, err)
+		return
+	}
+	writeJSON(w, d)
+}
+
+// Redeliver handles POST /api/admin/webhooks/deliveries/{id}/retry: requeues a
+// dead (or still pending) delivery for an immediate attempt.
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	d, err := h.dispatcher.Retry(chi.URLParam(r, "id"))
+	switch {
+	case errors.Is(err, webhook.ErrNotFound):
+		writeWebhookStoreError(w, r, err)
+		return
+	case err != nil:
+		writeError(w, r, http.StatusConflict, ErrorBody{Code: "conflict", Message: err.Error()})
+		return
+	}
+	writeJSON(w, d)
+}
+
+func validateSubscription(req subscriptionRequest) []FieldError {
+	var errs []FieldError
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, FieldError{Field: "url", Message: "must be an absolute http or https URL"})
+	}
+	for _, k := range req.Filter.Entities {
+		if _, err := watchlist.ParseKind(string(k)); err != nil {
+			errs = append(errs, FieldError{Field: "filter.entities", Message: "must list parcel, building or unit"})
+			break
+		}
+	}
+	for _, c := range req.Filter.Changes {
+		if !c.Valid() {
+			errs = append(errs, FieldError{Field: "filter.changes", Message: "unknown change kind " + strconv.Quote(string(c))})
+			break
+		}
+	}
+	return errs
+}
+
+// writeWebhookStoreError answers a webhook storage failure, 404 for unknown IDs.
+func writeWebhookStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, webhook.ErrNotFound) {
+		writeError(w, r, http.StatusNotFound, ErrorBody{Code: CodeNotFound, Message: "no such subscription or delivery"})
+		return
+	}
+	writeError(w, r, http.StatusInternalServerError, ErrorBody{Code: CodeInternal, Message: err.Error()})
+}
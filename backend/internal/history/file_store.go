@@ -0,0 +1,109 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileStore keeps the versions of each object in its own JSON file,
+// <dir>/<kind>/<id>.json.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStore creates a FileStore under dir; files are created on the first write.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Versions(kind string, id int64) ([]Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(kind, id)
+}
+
+func (s *FileStore) Append(kind string, id int64, v Version) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions, err := s.read(kind, id)
+	if err != nil {
+		return err
+	}
+	versions = append(versions, v)
+
+	path := s.path(kind, id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *FileStore) read(kind string, id int64) ([]Version, error) {
+	data, err := os.ReadFile(s.path(kind, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return []Version{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+func (s *FileStore) path(kind string, id int64) string {
+	return filepath.Join(s.dir, filepath.Base(kind), strconv.FormatInt(id, 10)+".json")
+}
+
+// Walk calls fn with the versions of every object in the store.
+func (s *FileStore) Walk(fn func(kind string, id int64, versions []Version) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kinds, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		if !kind.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(s.dir, kind.Name()))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			name, ok := strings.CutSuffix(f.Name(), ".json")
+			id, err := strconv.ParseInt(name, 10, 64)
+			if !ok || err != nil {
+				continue
+			}
+			versions, err := s.read(kind.Name(), id)
+			if err != nil {
+				return err
+			}
+			if err := fn(kind.Name(), id, versions); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
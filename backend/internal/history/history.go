@@ -0,0 +1,95 @@
+// Package history keeps every distinct version of the parcels, buildings and
+// units fetched from CUZK, so attribute changes such as a split or a
+// re-classification can be audited later.
+package history
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoVersion is returned by Select when no version matches.
+var ErrNoVersion = errors.New("history: no such version")
+
+// Version is one distinct state of an object.
+type Version struct {
+	Version int             `json:"version"` // 1 for the first version seen
+	SeenAt  time.Time       `json:"seenAt"`  // when this state was first fetched
+	Hash    string          `json:"hash"`    // SHA-256 of Data
+	Data    json.RawMessage `json:"data"`    // the object as returned by CUZK
+}
+
+// Store persists versions per object; kind is one of the cuzk.Object* kinds.
+type Store interface {
+	// Versions returns the versions of an object, oldest first; empty if never seen.
+	Versions(kind string, id int64) ([]Version, error)
+	Append(kind string, id int64, v Version) error
+}
+
+// FieldChange is a top-level attribute that differs between two versions.
+// From or To is absent when the attribute was added or removed.
+type FieldChange struct {
+	Field string          `json:"field"` // CUZK JSON name, e.g. "vymera", "druhPozemku", "cisloLV"
+	From  json.RawMessage `json:"from,omitempty"`
+	To    json.RawMessage `json:"to,omitempty"`
+}
+
+// Diff lists the attributes that changed from one version to another, sorted by field.
+func Diff(from, to Version) []FieldChange {
+	var a, b map[string]json.RawMessage
+	json.Unmarshal(from.Data, &a)
+	json.Unmarshal(to.Data, &b)
+
+	changes := []FieldChange{}
+	for field, v := range b {
+		if old, ok := a[field]; !ok || !bytes.Equal(old, v) {
+			changes = append(changes, FieldChange{Field: field, From: old, To: v})
+		}
+	}
+	for field, v := range a {
+		if _, ok := b[field]; !ok {
+			changes = append(changes, FieldChange{Field: field, From: v})
+		}
+	}
+	slices.SortFunc(changes, func(x, y FieldChange) int { return strings.Compare(x.Field, y.Field) })
+	return changes
+}
+
+// Select finds a version by number ("3") or by time: the version current at
+// an RFC 3339 timestamp or at the end of a date ("2025-04-08").
+func Select(versions []Version, spec string) (Version, error) {
+	if n, err := strconv.Atoi(spec); err == nil {
+		if n < 1 || n > len(versions) {
+			return Version{}, fmt.Errorf("%w: %d", ErrNoVersion, n)
+		}
+		return versions[n-1], nil
+	}
+
+	at, err := time.Parse(time.RFC3339, spec)
+	if err != nil {
+		day, dayErr := time.Parse(time.DateOnly, spec)
+		if dayErr != nil {
+			return Version{}, fmt.Errorf("version %q is neither a number, an RFC 3339 time nor a date", spec)
+		}
+		at = day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].SeenAt.After(at) {
+			return versions[i], nil
+		}
+	}
+	return Version{}, fmt.Errorf("%w: none seen by %s", ErrNoVersion, at.Format(time.RFC3339))
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,77 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+func TestRecorderKeepsDistinctVersions(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+	arable, garden, lv := "orná půda", "zahrada", "1187"
+	p := cuzk.Parcel{ID: 729272105, BaseNumber: 1521, CadastralArea: cuzk.CadastralArea{Code: 729272}, Area: 812, LandType: &arable}
+
+	rec := NewRecorder(store)
+	rec.Observe(ctx, cuzk.ObjectParcel, p.ID, &p)
+	rec.Observe(ctx, cuzk.ObjectParcel, p.ID, &p)
+
+	// A restarted recorder must pick up the stored history, not start over.
+	rec = NewRecorder(store)
+	rec.Observe(ctx, cuzk.ObjectParcel, p.ID, &p)
+	split := p
+	split.Area, split.LandType, split.OwnershipSheet = 406, &garden, &lv
+	rec.Observe(ctx, cuzk.ObjectParcel, p.ID, &split)
+
+	versions, err := store.Versions(cuzk.ObjectParcel, p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[1].Version != 2 {
+		t.Fatalf("expected 2 versions, got %+v", versions)
+	}
+
+	changes := Diff(versions[0], versions[1])
+	var fields []string
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	if len(changes) != 3 || fields[0] != "cisloLV" || changes[0].From != nil || fields[1] != "druhPozemku" || string(changes[2].To) != "406" {
+		t.Errorf("unexpected changes %v: %+v", fields, changes)
+	}
+
+	if v, err := Select(versions, "1"); err != nil || v.Version != 1 {
+		t.Errorf("Select by number = %+v, %v", v, err)
+	}
+	if v, err := Select(versions, time.Now().Format(time.RFC3339Nano)); err != nil || v.Version != 2 {
+		t.Errorf("Select by time = %+v, %v", v, err)
+	}
+	if _, err := Select(versions, "2000-01-01"); !errors.Is(err, ErrNoVersion) {
+		t.Errorf("expected ErrNoVersion before the first version, got %v", err)
+	}
+}
+
+func TestRecorderBoundsLatest(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileStore(t.TempDir())
+	rec := NewRecorder(store)
+	rec.limit = 2
+
+	parcels := make([]cuzk.Parcel, 3)
+	for i := range parcels {
+		parcels[i] = cuzk.Parcel{ID: int64(729272101 + i), BaseNumber: 1521 + i, Area: 812}
+		rec.Observe(ctx, cuzk.ObjectParcel, parcels[i].ID, &parcels[i])
+	}
+	if len(rec.latest) != 2 || rec.lru.Len() != 2 || len(rec.locks) != 0 {
+		t.Fatalf("latest = %d, lru = %d, locks = %d, want 2, 2, 0", len(rec.latest), rec.lru.Len(), len(rec.locks))
+	}
+
+	// The evicted parcel is reloaded from the store rather than recorded again.
+	rec.Observe(ctx, cuzk.ObjectParcel, parcels[0].ID, &parcels[0])
+	if versions, _ := store.Versions(cuzk.ObjectParcel, parcels[0].ID); len(versions) != 1 {
+		t.Errorf("expected 1 version after eviction, got %d", len(versions))
+	}
+}
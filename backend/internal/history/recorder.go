@@ -0,0 +1,144 @@
+package history
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxLatest bounds how many last versions a Recorder keeps in memory.
+const maxLatest = 10000
+
+// Recorder stores a new version whenever an observed object differs from its
+// last version; it implements cuzk.Observer.
+//
+// Store I/O runs under a lock per kind:id, so observations of different
+// objects proceed in parallel; r.mu only guards the maps below.
+type Recorder struct {
+	store Store
+
+	mu     sync.Mutex
+	locks  map[string]*keyLock      // held while an object's history is read or appended
+	latest map[string]*list.Element // last version per kind:id, loaded on first sight
+	lru    *list.List               // of *latestEntry, most recently used first
+	limit  int
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int // callers holding or waiting for the lock
+}
+
+type latestEntry struct {
+	key     string
+	version Version
+}
+
+// NewRecorder creates a Recorder writing to store.
+func NewRecorder(store Store) *Recorder {
+	return &Recorder{
+		store:  store,
+		locks:  make(map[string]*keyLock),
+		latest: make(map[string]*list.Element),
+		lru:    list.New(),
+		limit:  maxLatest,
+	}
+}
+
+// Observe records object as a new version of kind/id unless it is unchanged.
+// Failures are logged; they never fail the request that fetched the object.
+func (r *Recorder) Observe(ctx context.Context, kind string, id int64, object any) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		slog.Warn("history: encode object", "kind", kind, "id", id, "error", err)
+		return
+	}
+	h := hash(data)
+	key := kind + ":" + strconv.FormatInt(id, 10)
+
+	unlock := r.lock(key)
+	defer unlock()
+
+	last, ok := r.cached(key)
+	if !ok {
+		versions, err := r.store.Versions(kind, id)
+		if err != nil {
+			slog.Warn("history: load versions", "kind", kind, "id", id, "error", err)
+			return
+		}
+		if n := len(versions); n > 0 {
+			last = versions[n-1]
+		}
+	}
+	if last.Hash == h {
+		r.remember(key, Version{Version: last.Version, SeenAt: last.SeenAt, Hash: h})
+		return
+	}
+
+	v := Version{Version: last.Version + 1, SeenAt: time.Now(), Hash: h, Data: data}
+	if err := r.store.Append(kind, id, v); err != nil {
+		slog.Warn("history: save version", "kind", kind, "id", id, "error", err)
+		return
+	}
+	r.remember(key, Version{Version: v.Version, SeenAt: v.SeenAt, Hash: h}) // Data is not needed for comparison
+	if v.Version > 1 {
+		slog.Info("object changed upstream", "kind", kind, "id", id, "version", v.Version)
+	}
+}
+
+// lock takes the lock of key and returns its release. The lock is dropped
+// from r.locks once nobody holds or waits for it.
+func (r *Recorder) lock(key string) (unlock func()) {
+	r.mu.Lock()
+	l, ok := r.locks[key]
+	if !ok {
+		l = &keyLock{}
+		r.locks[key] = l
+	}
+	l.refs++
+	r.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		r.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(r.locks, key)
+		}
+		r.mu.Unlock()
+	}
+}
+
+// cached returns the remembered last version of key.
+func (r *Recorder) cached(key string) (Version, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.latest[key]
+	if !ok {
+		return Version{}, false
+	}
+	r.lru.MoveToFront(e)
+	return e.Value.(*latestEntry).version, true
+}
+
+// remember stores v as the last version of key, evicting the least recently
+// used entry beyond the limit; evicted keys are reloaded from the store.
+func (r *Recorder) remember(key string, v Version) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.latest[key]; ok {
+		e.Value.(*latestEntry).version = v
+		r.lru.MoveToFront(e)
+		return
+	}
+	r.latest[key] = r.lru.PushFront(&latestEntry{key: key, version: v})
+	if r.lru.Len() > r.limit {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.latest, oldest.Value.(*latestEntry).key)
+	}
+}
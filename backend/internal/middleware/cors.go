@@ -8,7 +8,8 @@ func CORS() cors.Options {
 	return cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "Authorization"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "Authorization", RequestIDHeader},
+		ExposedHeaders:   []string{RequestIDHeader, "X-Cache-Status"},
 		AllowCredentials: false,
 		MaxAge:           300,
 	}
@@ -28,6 +28,7 @@ func Logger(next http.Handler) http.Handler {
 			"path", r.URL.Path,
 			"status", wrapped.statusCode,
 			"duration", time.Since(start).String(),
+			"requestId", GetRequestID(r.Context()),
 		)
 	})
 }
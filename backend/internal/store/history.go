@@ -0,0 +1,52 @@
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/history"
+)
+
+// HistoryRepo implements history.Store.
+type HistoryRepo struct {
+	db *DB
+}
+
+// History returns the object history repository.
+func (db *DB) History() *HistoryRepo {
+	return &HistoryRepo{db: db}
+}
+
+var _ history.Store = (*HistoryRepo)(nil)
+
+func (r *HistoryRepo) Versions(kind string, id int64) ([]history.Version, error) {
+	prefix := historyPrefix(kind, id)
+	versions := []history.Version{}
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketHistory).Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var ver history.Version
+			if err := json.Unmarshal(v, &ver); err != nil {
+				return err
+			}
+			versions = append(versions, ver)
+		}
+		return nil
+	})
+	return versions, err
+}
+
+func (r *HistoryRepo) Append(kind string, id int64, v history.Version) error {
+	key := append(historyPrefix(kind, id), u64(uint64(v.Version))...)
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, bucketHistory, key, v)
+	})
+}
+
+// historyPrefix is "kind:id/"; the slash keeps parcel 12 from matching parcel 123.
+func historyPrefix(kind string, id int64) []byte {
+	return []byte(kind + ":" + strconv.FormatInt(id, 10) + "/")
+}
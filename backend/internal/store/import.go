@@ -0,0 +1,169 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/history"
+	"katastr-p6/backend/internal/warmer"
+	"katastr-p6/backend/internal/watchlist"
+	"katastr-p6/backend/internal/webhook"
+)
+
+// keyFilesImported marks a database into which ImportFiles has run.
+var keyFilesImported = []byte("files_imported")
+
+// FileStores are the JSON file stores state was kept in before the embedded
+// store; see ImportFiles.
+type FileStores struct {
+	Watchlist watchlist.Store
+	Webhooks  webhook.Store
+	History   *history.FileStore
+	Warmer    warmer.StateStore
+}
+
+// ImportCounts reports what ImportFiles copied.
+type ImportCounts struct {
+	Items, Snapshots, Events  int
+	Subscriptions, Deliveries int
+	Objects                   int // objects with history
+	Checkpoint                bool
+}
+
+// ImportFiles copies the file stores into the database once, on the first
+// open after upgrading, so existing watches, subscriptions, history and the
+// warming checkpoint carry over. An aggregate the database already holds
+// data for is left alone, as is every object that already has history.
+// Event sequence numbers are kept. The files themselves are not touched.
+func (db *DB) ImportFiles(src FileStores) (ImportCounts, error) {
+	var n ImportCounts
+	done := false
+	if err := db.bolt.View(func(tx *bolt.Tx) error {
+		done = tx.Bucket(bucketMeta).Get(keyFilesImported) != nil
+		return nil
+	}); err != nil || done {
+		return n, err
+	}
+
+	// Read everything first; the import itself is a single transaction.
+	items, err := src.Watchlist.Items()
+	if err != nil {
+		return n, fmt.Errorf("read watchlist: %w", err)
+	}
+	snapshots := map[string]watchlist.Snapshot{}
+	for _, it := range items {
+		s, err := src.Watchlist.Snapshot(it.Ref)
+		if err != nil {
+			return n, fmt.Errorf("read watchlist snapshot: %w", err)
+		}
+		if s != nil {
+			snapshots[it.Ref.String()] = *s
+		}
+	}
+	events, err := src.Watchlist.Events(watchlist.EventQuery{})
+	if err != nil {
+		return n, fmt.Errorf("read watchlist events: %w", err)
+	}
+	subs, err := src.Webhooks.Subscriptions()
+	if err != nil {
+		return n, fmt.Errorf("read webhook subscriptions: %w", err)
+	}
+	deliveries, err := src.Webhooks.Deliveries(webhook.DeliveryQuery{})
+	if err != nil {
+		return n, fmt.Errorf("read webhook deliveries: %w", err)
+	}
+	checkpoint, err := src.Warmer.Load()
+	if err != nil {
+		return n, fmt.Errorf("read warmer checkpoint: %w", err)
+	}
+	type object struct {
+		kind     string
+		id       int64
+		versions []history.Version
+	}
+	var objects []object
+	if err := src.History.Walk(func(kind string, id int64, versions []history.Version) error {
+		objects = append(objects, object{kind, id, versions})
+		return nil
+	}); err != nil {
+		return n, fmt.Errorf("read history: %w", err)
+	}
+
+	err = db.bolt.Update(func(tx *bolt.Tx) error {
+		empty := func(bucket []byte) bool {
+			k, _ := tx.Bucket(bucket).Cursor().First()
+			return k == nil
+		}
+
+		if empty(bucketWatchlistItems) {
+			for _, it := range items {
+				if err := putJSON(tx, bucketWatchlistItems, []byte(it.Ref.String()), it); err != nil {
+					return err
+				}
+			}
+			for ref, s := range snapshots {
+				if err := putJSON(tx, bucketWatchlistSnapshots, []byte(ref), s); err != nil {
+					return err
+				}
+			}
+			n.Items, n.Snapshots = len(items), len(snapshots)
+		}
+		if b := tx.Bucket(bucketWatchlistEvents); empty(bucketWatchlistEvents) && len(events) > 0 {
+			var last uint64
+			for _, e := range events {
+				seq := uint64(e.Seq)
+				if err := putJSON(tx, bucketWatchlistEvents, u64(seq), e); err != nil {
+					return err
+				}
+				last = max(last, seq)
+			}
+			if err := b.SetSequence(last); err != nil {
+				return err
+			}
+			n.Events = len(events)
+		}
+
+		if empty(bucketWebhookSubs) {
+			for _, s := range subs {
+				if err := putJSON(tx, bucketWebhookSubs, []byte(s.ID), s); err != nil {
+					return err
+				}
+			}
+			for _, d := range deliveries {
+				if err := putJSON(tx, bucketWebhookDeliveries, []byte(d.ID), d); err != nil {
+					return err
+				}
+			}
+			n.Subscriptions, n.Deliveries = len(subs), len(deliveries)
+		}
+
+		for _, o := range objects {
+			prefix := historyPrefix(o.kind, o.id)
+			if k, _ := tx.Bucket(bucketHistory).Cursor().Seek(prefix); k != nil && bytes.HasPrefix(k, prefix) {
+				continue
+			}
+			for _, v := range o.versions {
+				if err := putJSON(tx, bucketHistory, slices.Concat(prefix, u64(uint64(v.Version))), v); err != nil {
+					return err
+				}
+			}
+			n.Objects++
+		}
+
+		if checkpoint != nil && tx.Bucket(bucketJobs).Get(keyWarmer) == nil {
+			if err := putJSON(tx, bucketJobs, keyWarmer, checkpoint); err != nil {
+				return err
+			}
+			n.Checkpoint = true
+		}
+
+		return tx.Bucket(bucketMeta).Put(keyFilesImported, []byte("1"))
+	})
+	if err != nil {
+		return ImportCounts{}, fmt.Errorf("import file stores: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/warmer"
+)
+
+// WarmerRepo implements warmer.StateStore.
+type WarmerRepo struct {
+	db *DB
+}
+
+// Warmer returns the cache warmer checkpoint repository.
+func (db *DB) Warmer() *WarmerRepo {
+	return &WarmerRepo{db: db}
+}
+
+var _ warmer.StateStore = (*WarmerRepo)(nil)
+
+var keyWarmer = []byte("warmer")
+
+func (r *WarmerRepo) Load() (*warmer.Checkpoint, error) {
+	var cp warmer.Checkpoint
+	var found bool
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(tx, bucketJobs, keyWarmer, &cp)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (r *WarmerRepo) Save(cp *warmer.Checkpoint) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, bucketJobs, keyWarmer, cp)
+	})
+}
+
+// Reset deletes the checkpoint so the next run starts over.
+func (r *WarmerRepo) Reset() error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketJobs).Delete(keyWarmer)
+	})
+}
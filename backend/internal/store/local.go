@@ -0,0 +1,186 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/vfk"
+)
+
+// LocalRepo holds parcels, buildings, units and ownership sheets imported
+// from VFK extracts and implements cuzk.LocalSource.
+type LocalRepo struct {
+	db *DB
+}
+
+// Local returns the imported cadastral data repository.
+func (db *DB) Local() *LocalRepo {
+	return &LocalRepo{db: db}
+}
+
+var _ cuzk.LocalSource = (*LocalRepo)(nil)
+
+// LocalCounts reports how many objects the repository holds.
+type LocalCounts struct {
+	Parcels         int `json:"parcels"`
+	Buildings       int `json:"buildings"`
+	Units           int `json:"units"`
+	OwnershipSheets int `json:"ownershipSheets"`
+}
+
+// Import stores the objects of an extract in one transaction, overwriting
+// objects with the same ID. With replace, previously imported data is
+// dropped first, so objects cancelled since the last extract disappear.
+func (r *LocalRepo) Import(x *vfk.Extract, replace bool) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		if replace {
+			for _, name := range localBuckets {
+				if err := tx.DeleteBucket(name); err != nil {
+					return err
+				}
+				if _, err := tx.CreateBucket(name); err != nil {
+					return err
+				}
+			}
+		}
+		for _, p := range x.Parcels {
+			if err := putJSON(tx, bucketLocalParcels, u64(uint64(p.ID)), p); err != nil {
+				return err
+			}
+			key := parcelNumberKey(p.CadastralArea.Code, p.BaseNumber, p.ID)
+			if err := tx.Bucket(bucketLocalParcelNumbers).Put(key, nil); err != nil {
+				return err
+			}
+		}
+		for id, neighbors := range x.Neighbors {
+			if err := putJSON(tx, bucketLocalNeighbors, u64(uint64(id)), neighbors); err != nil {
+				return err
+			}
+		}
+		for _, b := range x.Buildings {
+			if err := putJSON(tx, bucketLocalBuildings, u64(uint64(b.ID)), b); err != nil {
+				return err
+			}
+		}
+		for _, u := range x.Units {
+			if err := putJSON(tx, bucketLocalUnits, u64(uint64(u.ID)), u); err != nil {
+				return err
+			}
+		}
+		for _, lv := range x.OwnershipSheets {
+			if err := putJSON(tx, bucketLocalSheets, sheetKey(lv.CadastralArea.Code, lv.Number), lv); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Counts returns the number of objects held.
+func (r *LocalRepo) Counts() (LocalCounts, error) {
+	var c LocalCounts
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		c.Parcels = tx.Bucket(bucketLocalParcels).Stats().KeyN
+		c.Buildings = tx.Bucket(bucketLocalBuildings).Stats().KeyN
+		c.Units = tx.Bucket(bucketLocalUnits).Stats().KeyN
+		c.OwnershipSheets = tx.Bucket(bucketLocalSheets).Stats().KeyN
+		return nil
+	})
+	return c, err
+}
+
+func (r *LocalRepo) LocalParcel(ctx context.Context, id int64) (*cuzk.Parcel, error) {
+	return getLocal[cuzk.Parcel](r.db, bucketLocalParcels, u64(uint64(id)))
+}
+
+func (r *LocalRepo) LocalBuilding(ctx context.Context, id int64) (*cuzk.Building, error) {
+	return getLocal[cuzk.Building](r.db, bucketLocalBuildings, u64(uint64(id)))
+}
+
+func (r *LocalRepo) LocalUnit(ctx context.Context, id int64) (*cuzk.Unit, error) {
+	return getLocal[cuzk.Unit](r.db, bucketLocalUnits, u64(uint64(id)))
+}
+
+func (r *LocalRepo) LocalOwnershipSheet(ctx context.Context, areaCode, number int) (*cuzk.OwnershipSheet, error) {
+	return getLocal[cuzk.OwnershipSheet](r.db, bucketLocalSheets, sheetKey(areaCode, strconv.Itoa(number)))
+}
+
+// LocalParcels returns the parcels with the base number of number in an
+// area; the client narrows them down to the subdivision and numbering type.
+func (r *LocalRepo) LocalParcels(ctx context.Context, areaCode int, number cuzk.ParcelNumber) (*cuzk.ParcelSearchResponse, error) {
+	var parcels []cuzk.Parcel
+	prefix := parcelNumberKey(areaCode, number.Base, 0)[:16]
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketLocalParcelNumbers).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			var p cuzk.Parcel
+			found, err := getJSON(tx, bucketLocalParcels, k[16:], &p)
+			if err != nil {
+				return err
+			}
+			if found {
+				parcels = append(parcels, p)
+			}
+		}
+		return nil
+	})
+	if err != nil || len(parcels) == 0 {
+		return nil, err
+	}
+	return &cuzk.ParcelSearchResponse{Parcels: parcels, Total: len(parcels)}, nil
+}
+
+func (r *LocalRepo) LocalNeighbors(ctx context.Context, id int64) (*cuzk.NeighborParcelsResponse, error) {
+	var resp *cuzk.NeighborParcelsResponse
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		var ids []int64
+		found, err := getJSON(tx, bucketLocalNeighbors, u64(uint64(id)), &ids)
+		if err != nil || !found {
+			return err
+		}
+		resp = &cuzk.NeighborParcelsResponse{ParcelID: id, Neighbors: []cuzk.Parcel{}}
+		for _, n := range ids {
+			var p cuzk.Parcel
+			found, err := getJSON(tx, bucketLocalParcels, u64(uint64(n)), &p)
+			if err != nil {
+				return err
+			}
+			if found {
+				resp.Neighbors = append(resp.Neighbors, p)
+			}
+		}
+		return nil
+	})
+	return resp, err
+}
+
+// getLocal decodes one object, nil when it is not held.
+func getLocal[T any](db *DB, bucket, key []byte) (*T, error) {
+	var v T
+	var found bool
+	err := db.bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(tx, bucket, key, &v)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// parcelNumberKey orders parcels by area and base number, so a prefix scan
+// over the first 16 bytes finds every subdivision of a base number.
+func parcelNumberKey(area, base int, id int64) []byte {
+	key := u64(uint64(area))
+	key = append(key, u64(uint64(base))...)
+	return append(key, u64(uint64(id))...)
+}
+
+func sheetKey(area int, number string) []byte {
+	return []byte(strconv.Itoa(area) + "/" + number)
+}
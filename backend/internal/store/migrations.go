@@ -0,0 +1,118 @@
+package store
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets.
+var (
+	bucketMeta               = []byte("meta")
+	bucketWatchlistItems     = []byte("watchlist_items")     // "kind:id" -> watchlist.Item
+	bucketWatchlistSnapshots = []byte("watchlist_snapshots") // "kind:id" -> watchlist.Snapshot
+	bucketWatchlistEvents    = []byte("watchlist_events")    // seq -> watchlist.Event
+	bucketWebhookSubs        = []byte("webhook_subscriptions")
+	bucketWebhookDeliveries  = []byte("webhook_deliveries")
+	bucketHistory            = []byte("history") // "kind:id/" + version -> history.Version
+	bucketJobs               = []byte("jobs")    // job name -> checkpoint
+	bucketKeyUsage           = []byte("api_key_usage")
+
+	// Objects imported from VFK extracts, see LocalRepo.
+	bucketLocalParcels       = []byte("local_parcels")          // id -> cuzk.Parcel
+	bucketLocalParcelNumbers = []byte("local_parcel_numbers")   // area, base number, id -> nothing
+	bucketLocalNeighbors     = []byte("local_neighbors")        // parcel id -> []int64
+	bucketLocalBuildings     = []byte("local_buildings")        // id -> cuzk.Building
+	bucketLocalUnits         = []byte("local_units")            // id -> cuzk.Unit
+	bucketLocalSheets        = []byte("local_ownership_sheets") // area, LV number -> cuzk.OwnershipSheet
+)
+
+// localBuckets hold the imported VFK data.
+var localBuckets = [][]byte{
+	bucketLocalParcels, bucketLocalParcelNumbers, bucketLocalNeighbors,
+	bucketLocalBuildings, bucketLocalUnits, bucketLocalSheets,
+}
+
+var keySchemaVersion = []byte("schema_version")
+
+// migration upgrades the schema to version. Migrations run in order, each in
+// its own transaction together with the version bump; never edit a released
+// migration, append a new one.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *bolt.Tx) error
+}
+
+var migrations = []migration{
+	{1, "create buckets", func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{
+			bucketWatchlistItems, bucketWatchlistSnapshots, bucketWatchlistEvents,
+			bucketWebhookSubs, bucketWebhookDeliveries,
+			bucketHistory, bucketJobs, bucketKeyUsage,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{2, "create local data buckets", func(tx *bolt.Tx) error {
+		for _, b := range localBuckets {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+}
+
+// SchemaVersion returns the version of the newest migration.
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate applies the migrations newer than the stored schema version.
+func (db *DB) migrate() error {
+	current, err := db.schemaVersion()
+	if err != nil {
+		return err
+	}
+	if current > SchemaVersion() {
+		return fmt.Errorf("store schema version %d is newer than this build supports (%d)", current, SchemaVersion())
+	}
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err := db.bolt.Update(func(tx *bolt.Tx) error {
+			if err := m.up(tx); err != nil {
+				return err
+			}
+			return tx.Bucket(bucketMeta).Put(keySchemaVersion, []byte(strconv.Itoa(m.version)))
+		})
+		if err != nil {
+			return fmt.Errorf("store migration %d (%s): %w", m.version, m.name, err)
+		}
+		slog.Info("store migrated", "version", m.version, "migration", m.name)
+	}
+	return nil
+}
+
+// schemaVersion returns the stored schema version, 0 for a new database.
+func (db *DB) schemaVersion() (int, error) {
+	version := 0
+	err := db.bolt.Update(func(tx *bolt.Tx) error {
+		meta, err := tx.CreateBucketIfNotExists(bucketMeta)
+		if err != nil {
+			return err
+		}
+		if v := meta.Get(keySchemaVersion); v != nil {
+			version, err = strconv.Atoi(string(v))
+		}
+		return err
+	})
+	return version, err
+}
@@ -0,0 +1,149 @@
+// Package store keeps backend state (watchlist, webhooks, object history, job
+// checkpoints and API key usage) in an embedded bbolt database file, so it
+// survives restarts without a database server. Each aggregate has a
+// repository implementing the storage interface of its package.
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
+)
+
+// ErrLocked is returned by Open when another process has the database open,
+// typically the running server.
+var ErrLocked = errors.New("store: database is locked by another process")
+
+// lockTimeout is how long Open waits for the file lock.
+const lockTimeout = time.Second
+
+// DB is the embedded database.
+type DB struct {
+	bolt *bolt.DB
+}
+
+// Open opens or creates the database at path and applies pending migrations.
+func Open(path string) (*DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	b, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, berrors.ErrTimeout) {
+		return nil, fmt.Errorf("%w: %s", ErrLocked, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open store: %w", err)
+	}
+	db := &DB{bolt: b}
+	if err := db.migrate(); err != nil {
+		b.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close closes the database file.
+func (db *DB) Close() error {
+	return db.bolt.Close()
+}
+
+// Path returns the database file path.
+func (db *DB) Path() string {
+	return db.bolt.Path()
+}
+
+// Backup writes a consistent copy of the database to w while it stays in use.
+func (db *DB) Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := db.bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// Restore replaces the database at path with the backup read from r. The
+// database must not be open; the backup is checked and migrated before it
+// replaces the current file.
+func Restore(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		// Fail early, before touching anything, if the server holds the lock.
+		db, err := Open(path)
+		if err != nil {
+			return err
+		}
+		db.Close()
+	}
+
+	tmp := path + ".restore"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("write backup: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	db, err := Open(tmp)
+	if err != nil {
+		return fmt.Errorf("backup is not a valid store: %w", err)
+	}
+	if err := db.check(); err != nil {
+		db.Close()
+		return fmt.Errorf("backup is corrupt: %w", err)
+	}
+	db.Close()
+	return os.Rename(tmp, path)
+}
+
+// check verifies the consistency of every page.
+func (db *DB) check() error {
+	return db.bolt.View(func(tx *bolt.Tx) error {
+		var first error
+		for err := range tx.Check() {
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	})
+}
+
+// getJSON decodes the value of key in bucket into v; found is false when the key does not exist.
+func getJSON(tx *bolt.Tx, bucket, key []byte, v any) (found bool, err error) {
+	data := tx.Bucket(bucket).Get(key)
+	if data == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func putJSON(tx *bolt.Tx, bucket, key []byte, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(bucket).Put(key, data)
+}
+
+// u64 encodes n big-endian so keys sort numerically.
+func u64(n uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, n)
+}
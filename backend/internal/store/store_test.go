@@ -0,0 +1,170 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+	"katastr-p6/backend/internal/history"
+	"katastr-p6/backend/internal/vfk"
+	"katastr-p6/backend/internal/warmer"
+	"katastr-p6/backend/internal/watchlist"
+	"katastr-p6/backend/internal/webhook"
+)
+
+func TestBackupRestoreKeepsState(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(filepath.Join(dir, "katastr.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := db.schemaVersion(); err != nil || v != SchemaVersion() {
+		t.Fatalf("schema version %d (%v), want %d", v, err, SchemaVersion())
+	}
+	if _, err := Open(db.Path()); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second open: got %v, want ErrLocked", err)
+	}
+
+	ref := watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 729272105}
+	if err := db.Watchlist().PutItem(watchlist.Item{Ref: ref, Label: "zahrada", CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	events, err := db.Watchlist().AppendEvents([]watchlist.Event{{Ref: ref, Change: watchlist.ChangeSealAdded}, {Ref: ref, Change: watchlist.ChangeSealRemoved}})
+	if err != nil || events[1].Seq != 2 {
+		t.Fatalf("append events: %+v, %v", events, err)
+	}
+	// The history of parcel 7292721 must not pick up parcel 72927210.
+	for _, id := range []int64{7292721, 72927210} {
+		if err := db.History().Append(cuzk.ObjectParcel, id, history.Version{Version: 1, Data: []byte(`{"id":1}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var backup bytes.Buffer
+	if _, err := db.Backup(&backup); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	restored := filepath.Join(dir, "restored", "katastr.db")
+	if err := Restore(restored, bytes.NewReader(backup.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(restored, bytes.NewReader([]byte("not a database"))); err == nil {
+		t.Fatal("restoring garbage should fail")
+	}
+
+	db, err = Open(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	item, err := db.Watchlist().Item(ref)
+	if err != nil || item.Label != "zahrada" {
+		t.Fatalf("item after restore: %+v, %v", item, err)
+	}
+	events, err = db.Watchlist().Events(watchlist.EventQuery{AfterSeq: 1})
+	if err != nil || len(events) != 1 || events[0].Change != watchlist.ChangeSealRemoved {
+		t.Fatalf("events after seq 1: %+v, %v", events, err)
+	}
+	versions, err := db.History().Versions(cuzk.ObjectParcel, 7292721)
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("versions: %+v, %v", versions, err)
+	}
+}
+
+func TestLocalRepoFindsImportedParcels(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(filepath.Join(t.TempDir(), "katastr.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dejvice := cuzk.CadastralArea{Code: 729272, Name: "Dejvice"}
+	sub := 2
+	x := &vfk.Extract{
+		Parcels: []cuzk.Parcel{
+			{ID: 1, BaseNumber: 1521, CadastralArea: dejvice},
+			{ID: 2, BaseNumber: 1521, Subdivision: &sub, CadastralArea: dejvice},
+			{ID: 3, BaseNumber: 15210, CadastralArea: dejvice},
+		},
+		Neighbors: map[int64][]int64{1: {2, 99}},
+	}
+	if err := db.Local().Import(x, false); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := db.Local().LocalParcels(ctx, dejvice.Code, cuzk.ParcelNumber{Base: 1521})
+	if err != nil || resp.Total != 2 {
+		t.Fatalf("parcels 1521: %+v, %v", resp, err)
+	}
+	neighbors, err := db.Local().LocalNeighbors(ctx, 1)
+	if err != nil || len(neighbors.Neighbors) != 1 || neighbors.Neighbors[0].ID != 2 {
+		t.Fatalf("neighbors: %+v, %v", neighbors, err)
+	}
+	if p, err := db.Local().LocalParcel(ctx, 4); p != nil || err != nil {
+		t.Fatalf("unknown parcel: %+v, %v", p, err)
+	}
+
+	if err := db.Local().Import(&vfk.Extract{}, true); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := db.Local().Counts(); c.Parcels != 0 {
+		t.Fatalf("replace kept %d parcels", c.Parcels)
+	}
+}
+
+func TestImportFilesCarriesOverFileStores(t *testing.T) {
+	dir := t.TempDir()
+	files := FileStores{
+		Watchlist: watchlist.NewFileStore(filepath.Join(dir, "watchlist.json")),
+		Webhooks:  webhook.NewFileStore(filepath.Join(dir, "webhooks.json")),
+		History:   history.NewFileStore(filepath.Join(dir, "history")),
+		Warmer:    warmer.NewFileStateStore(filepath.Join(dir, "warmer-state.json")),
+	}
+	ref := watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 729272105}
+	files.Watchlist.PutItem(watchlist.Item{Ref: ref, Label: "zahrada", CreatedAt: time.Now()})
+	files.Watchlist.PutSnapshot(ref, watchlist.Snapshot{CheckedAt: time.Now(), Proceedings: map[int64]string{}})
+	files.Watchlist.AppendEvents([]watchlist.Event{{Ref: ref, Change: watchlist.ChangeSealAdded}, {Ref: ref, Change: watchlist.ChangeSealRemoved}})
+	files.Webhooks.PutSubscription(webhook.Subscription{ID: "sub1", URL: "https://example.com/hook", Secret: "s3cret"})
+	files.History.Append(cuzk.ObjectParcel, ref.ObjectID, history.Version{Version: 1, Data: []byte(`{"id":1}`)})
+	files.Warmer.Save(&warmer.Checkpoint{Number: 42})
+
+	db, err := Open(filepath.Join(dir, "katastr.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	n, err := db.ImportFiles(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != (ImportCounts{Items: 1, Snapshots: 1, Events: 2, Subscriptions: 1, Objects: 1, Checkpoint: true}) {
+		t.Errorf("imported %+v", n)
+	}
+
+	if s, err := db.Webhooks().Subscription("sub1"); err != nil || s.Secret != "s3cret" {
+		t.Errorf("subscription: %+v, %v", s, err)
+	}
+	if v, err := db.History().Versions(cuzk.ObjectParcel, ref.ObjectID); err != nil || len(v) != 1 {
+		t.Errorf("history: %+v, %v", v, err)
+	}
+	if cp, err := db.Warmer().Load(); err != nil || cp == nil || cp.Number != 42 {
+		t.Errorf("checkpoint: %+v, %v", cp, err)
+	}
+	// Sequence numbers continue after the imported events.
+	if events, err := db.Watchlist().AppendEvents([]watchlist.Event{{Ref: ref, Change: watchlist.ChangeSealAdded}}); err != nil || events[0].Seq != 3 {
+		t.Errorf("next event: %+v, %v", events, err)
+	}
+
+	// The import runs once; later edits to the files are not picked up.
+	files.Watchlist.PutItem(watchlist.Item{Ref: watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 1}})
+	if n, err := db.ImportFiles(files); err != nil || n != (ImportCounts{}) {
+		t.Errorf("second import: %+v, %v", n, err)
+	}
+}
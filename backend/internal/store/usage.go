@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// errNoUsage is returned by UsageRepo.Get for a missing or expired counter.
+var errNoUsage = errors.New("store: no usage recorded")
+
+// UsageRepo implements cuzk.UsageStore, keeping API key request counters.
+type UsageRepo struct {
+	db *DB
+}
+
+// Usage returns the API key usage repository.
+func (db *DB) Usage() *UsageRepo {
+	return &UsageRepo{db: db}
+}
+
+var _ cuzk.UsageStore = (*UsageRepo)(nil)
+
+type usageEntry struct {
+	Value     string    `json:"value"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func (r *UsageRepo) Get(ctx context.Context, key string) (string, error) {
+	var e usageEntry
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		found, err := getJSON(tx, bucketKeyUsage, []byte(key), &e)
+		if err == nil && (!found || time.Now().After(e.ExpiresAt)) {
+			return errNoUsage
+		}
+		return err
+	})
+	return e.Value, err
+}
+
+// Set stores a counter and drops expired ones.
+func (r *UsageRepo) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	now := time.Now()
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		if err := putJSON(tx, bucketKeyUsage, []byte(key), usageEntry{Value: value, ExpiresAt: now.Add(ttl)}); err != nil {
+			return err
+		}
+		return deleteExpiredUsage(tx, now)
+	})
+}
+
+func deleteExpiredUsage(tx *bolt.Tx, now time.Time) error {
+	b := tx.Bucket(bucketKeyUsage)
+	var expired [][]byte
+	err := b.ForEach(func(k, v []byte) error {
+		var e usageEntry
+		if json.Unmarshal(v, &e) == nil && now.After(e.ExpiresAt) {
+			expired = append(expired, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package store
+
+import (
+	"encoding/json"
+	"slices"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/watchlist"
+)
+
+// WatchlistRepo implements watchlist.Store.
+type WatchlistRepo struct {
+	db *DB
+}
+
+// Watchlist returns the watchlist repository.
+func (db *DB) Watchlist() *WatchlistRepo {
+	return &WatchlistRepo{db: db}
+}
+
+var _ watchlist.Store = (*WatchlistRepo)(nil)
+
+func (r *WatchlistRepo) Items() ([]watchlist.Item, error) {
+	items := []watchlist.Item{}
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketWatchlistItems).ForEach(func(_, v []byte) error {
+			var it watchlist.Item
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			items = append(items, it)
+			return nil
+		})
+	})
+	slices.SortFunc(items, func(a, b watchlist.Item) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return items, err
+}
+
+func (r *WatchlistRepo) Item(ref watchlist.Ref) (*watchlist.Item, error) {
+	var it watchlist.Item
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		found, err := getJSON(tx, bucketWatchlistItems, []byte(ref.String()), &it)
+		if err == nil && !found {
+			return watchlist.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+func (r *WatchlistRepo) PutItem(item watchlist.Item) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, bucketWatchlistItems, []byte(item.Ref.String()), item)
+	})
+}
+
+func (r *WatchlistRepo) UpdateItem(ref watchlist.Ref, fn func(*watchlist.Item)) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		var it watchlist.Item
+		found, err := getJSON(tx, bucketWatchlistItems, []byte(ref.String()), &it)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return watchlist.ErrNotFound
+		}
+		fn(&it)
+		return putJSON(tx, bucketWatchlistItems, []byte(ref.String()), it)
+	})
+}
+
+func (r *WatchlistRepo) DeleteItem(ref watchlist.Ref) error {
+	key := []byte(ref.String())
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		items := tx.Bucket(bucketWatchlistItems)
+		if items.Get(key) == nil {
+			return watchlist.ErrNotFound
+		}
+		if err := items.Delete(key); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketWatchlistSnapshots).Delete(key)
+	})
+}
+
+func (r *WatchlistRepo) Snapshot(ref watchlist.Ref) (*watchlist.Snapshot, error) {
+	var snap watchlist.Snapshot
+	var found bool
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		found, err = getJSON(tx, bucketWatchlistSnapshots, []byte(ref.String()), &snap)
+		return err
+	})
+	if err != nil || !found {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+func (r *WatchlistRepo) PutSnapshot(ref watchlist.Ref, s watchlist.Snapshot) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, bucketWatchlistSnapshots, []byte(ref.String()), s)
+	})
+}
+
+func (r *WatchlistRepo) AppendEvents(events []watchlist.Event) ([]watchlist.Event, error) {
+	if len(events) == 0 {
+		return events, nil
+	}
+	out := slices.Clone(events)
+	err := r.db.bolt.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketWatchlistEvents)
+		for i := range out {
+			seq, err := b.NextSequence()
+			if err != nil {
+				return err
+			}
+			out[i].Seq = int64(seq)
+			if err := putJSON(tx, bucketWatchlistEvents, u64(seq), out[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *WatchlistRepo) Events(q watchlist.EventQuery) ([]watchlist.Event, error) {
+	out := []watchlist.Event{}
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketWatchlistEvents).Cursor()
+		for k, v := c.Seek(u64(uint64(q.AfterSeq) + 1)); k != nil; k, v = c.Next() {
+			var e watchlist.Event
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			if q.Ref == nil || e.Ref == *q.Ref {
+				out = append(out, e)
+			}
+		}
+		return nil
+	})
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, err
+}
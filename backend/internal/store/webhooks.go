@@ -0,0 +1,201 @@
+package store
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+
+	"katastr-p6/backend/internal/webhook"
+)
+
+// maxFinishedDeliveries bounds how many delivered and dead deliveries are
+// kept; the oldest are dropped first. Pending deliveries are always kept.
+const maxFinishedDeliveries = 10000
+
+// WebhookRepo implements webhook.Store.
+type WebhookRepo struct {
+	db *DB
+}
+
+// Webhooks returns the webhook repository.
+func (db *DB) Webhooks() *WebhookRepo {
+	return &WebhookRepo{db: db}
+}
+
+var _ webhook.Store = (*WebhookRepo)(nil)
+
+func (r *WebhookRepo) Subscriptions() ([]webhook.Subscription, error) {
+	subs := []webhook.Subscription{}
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketWebhookSubs).ForEach(func(_, v []byte) error {
+			var s webhook.Subscription
+			if err := json.Unmarshal(v, &s); err != nil {
+				return err
+			}
+			subs = append(subs, s)
+			return nil
+		})
+	})
+	slices.SortFunc(subs, func(a, b webhook.Subscription) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return subs, err
+}
+
+func (r *WebhookRepo) Subscription(id string) (*webhook.Subscription, error) {
+	var s webhook.Subscription
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		found, err := getJSON(tx, bucketWebhookSubs, []byte(id), &s)
+		if err == nil && !found {
+			return webhook.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *WebhookRepo) PutSubscription(s webhook.Subscription) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx, bucketWebhookSubs, []byte(s.ID), s)
+	})
+}
+
+func (r *WebhookRepo) DeleteSubscription(id string) error {
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		subs := tx.Bucket(bucketWebhookSubs)
+		if subs.Get([]byte(id)) == nil {
+			return webhook.ErrNotFound
+		}
+		if err := subs.Delete([]byte(id)); err != nil {
+			return err
+		}
+		deliveries, err := loadDeliveries(tx)
+		if err != nil {
+			return err
+		}
+		for _, d := range deliveries {
+			if d.SubscriptionID == id && d.Status == webhook.StatusPending {
+				if err := tx.Bucket(bucketWebhookDeliveries).Delete([]byte(d.ID)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *WebhookRepo) Delivery(id string) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		found, err := getJSON(tx, bucketWebhookDeliveries, []byte(id), &d)
+		if err == nil && !found {
+			return webhook.ErrNotFound
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *WebhookRepo) PutDeliveries(ds ...webhook.Delivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	return r.db.bolt.Update(func(tx *bolt.Tx) error {
+		finishing := false
+		for _, d := range ds {
+			if err := putJSON(tx, bucketWebhookDeliveries, []byte(d.ID), d); err != nil {
+				return err
+			}
+			finishing = finishing || d.Status != webhook.StatusPending
+		}
+		if !finishing {
+			return nil
+		}
+		return trimDeliveries(tx)
+	})
+}
+
+func (r *WebhookRepo) Deliveries(q webhook.DeliveryQuery) ([]webhook.Delivery, error) {
+	var all []webhook.Delivery
+	err := r.db.bolt.View(func(tx *bolt.Tx) error {
+		var err error
+		all, err = loadDeliveries(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := []webhook.Delivery{}
+	for _, d := range all {
+		if q.Status != "" && d.Status != q.Status {
+			continue
+		}
+		if q.SubscriptionID != "" && d.SubscriptionID != q.SubscriptionID {
+			continue
+		}
+		if q.DueBy != nil && (d.Status != webhook.StatusPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(*q.DueBy)) {
+			continue
+		}
+		out = append(out, d)
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// loadDeliveries returns every delivery, oldest first.
+func loadDeliveries(tx *bolt.Tx) ([]webhook.Delivery, error) {
+	var out []webhook.Delivery
+	err := tx.Bucket(bucketWebhookDeliveries).ForEach(func(_, v []byte) error {
+		var d webhook.Delivery
+		if err := json.Unmarshal(v, &d); err != nil {
+			return err
+		}
+		out = append(out, d)
+		return nil
+	})
+	slices.SortFunc(out, func(a, b webhook.Delivery) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return out, err
+}
+
+// trimDeliveries drops the oldest finished deliveries beyond maxFinishedDeliveries.
+func trimDeliveries(tx *bolt.Tx) error {
+	b := tx.Bucket(bucketWebhookDeliveries)
+	if b.Stats().KeyN <= maxFinishedDeliveries {
+		return nil
+	}
+	all, err := loadDeliveries(tx)
+	if err != nil {
+		return err
+	}
+	finished := 0
+	for _, d := range all {
+		if d.Status != webhook.StatusPending {
+			finished++
+		}
+	}
+	for _, d := range all {
+		if finished <= maxFinishedDeliveries {
+			break
+		}
+		if d.Status != webhook.StatusPending {
+			if err := b.Delete([]byte(d.ID)); err != nil {
+				return err
+			}
+			finished--
+		}
+	}
+	return nil
+}
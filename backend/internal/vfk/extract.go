@@ -0,0 +1,584 @@
+package vfk
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// Codes of DRUH_CISLOVANI_PAR and TYPBUD_KOD.
+const (
+	numberingBuilding = 1 // stavební parcela
+	typeDescriptiveNo = 1 // budova s číslem popisným
+	typeEvidenceNo    = 2 // budova s číslem evidenčním
+)
+
+// OPSUB_TYPE values.
+const (
+	personNatural = "OFO" // fyzická osoba
+	personLegal   = "OPO" // právnická osoba
+	personSJM     = "BSM" // společné jmění manželů
+)
+
+// codeLists are the blocks mapping a KOD to a NAZEV used in the models.
+var codeLists = []string{"KATUZE", "CASOBC", "DRUPOZ", "ZPVYPA", "TYPBUD", "ZPVYBU", "TYPJED", "ZPVYJE", "TYPRAV"}
+
+// kept are the blocks whose rows are kept until mapping.
+var kept = []string{"PAR", "BUD", "JED", "TEL", "VLA", "JPV", "OPSUB", "OBDEBO", "HP"}
+
+// Extract is the content of a VFK file mapped to the cuzk models.
+type Extract struct {
+	Created         time.Time // &HVYTVORENO, zero when missing
+	Parcels         []cuzk.Parcel
+	Buildings       []cuzk.Building
+	Units           []cuzk.Unit
+	OwnershipSheets []cuzk.OwnershipSheet
+	// Neighbors maps a parcel ID to the parcels it shares a boundary (HP) with.
+	Neighbors map[int64][]int64
+	// Rows counts the rows read per block, including blocks that are not mapped.
+	Rows map[string]int
+}
+
+// point is an S-JTSK point from SOBR.
+type point struct{ y, x float64 }
+
+// extractor collects the rows needed for mapping.
+type extractor struct {
+	rows   map[string][]*Row
+	byID   map[string]map[int64]*Row
+	codes  map[string]map[string]string // block -> KOD -> NAZEV
+	points map[int64]point
+	lines  map[int64][]int64 // HP ID -> SOBR point IDs
+	counts map[string]int
+}
+
+// Read parses a VFK file and maps its current (not cancelled) records. Only
+// geometry of parcel boundaries (SBP rows with HP_ID) is used, for reference
+// points when OBDEBO has none. Blocks that are not mapped, such as SBM (map
+// symbols) and RIZENI (proceedings), only show up in Rows; encumbrances are
+// therefore mapped without the document that recorded them.
+func Read(r io.Reader) (*Extract, error) {
+	e := &extractor{
+		rows:   map[string][]*Row{},
+		byID:   map[string]map[int64]*Row{},
+		codes:  map[string]map[string]string{},
+		points: map[int64]point{},
+		lines:  map[int64][]int64{},
+		counts: map[string]int{},
+	}
+	vr := NewReader(r)
+	for {
+		row, err := vr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		e.add(row)
+	}
+
+	out := &Extract{Neighbors: e.neighbors(), Rows: e.counts}
+	if created := vr.Header("VYTVORENO"); len(created) > 0 {
+		out.Created, _ = time.ParseInLocation("02.01.2006 15:04:05", created[0], location)
+	}
+	out.Parcels = e.parcels()
+	out.Buildings = e.buildings(out.Parcels)
+	out.Units = e.units(out.Buildings)
+	out.OwnershipSheets = e.ownershipSheets(out)
+	return out, nil
+}
+
+func (e *extractor) add(row *Row) {
+	name := row.Block.Name
+	e.counts[name]++
+	if !row.IsNull("DATUM_ZANIKU") {
+		return
+	}
+	switch {
+	case slices.Contains(codeLists, name):
+		if e.codes[name] == nil {
+			e.codes[name] = map[string]string{}
+		}
+		e.codes[name][codeKey(row.String("KOD"))] = row.String("NAZEV")
+	case slices.Contains(kept, name):
+		e.rows[name] = append(e.rows[name], row)
+		if id, ok := row.Int("ID"); ok {
+			if e.byID[name] == nil {
+				e.byID[name] = map[int64]*Row{}
+			}
+			e.byID[name][id] = row
+		}
+	case name == "SOBR":
+		id, ok := row.Int("ID")
+		y, okY := row.Float("SOURADNICE_Y")
+		x, okX := row.Float("SOURADNICE_X")
+		if ok && okY && okX {
+			e.points[id] = point{y: math.Abs(y), x: math.Abs(x)}
+		}
+	case name == "SBP":
+		hp, ok := row.Int("HP_ID")
+		bp, okBP := row.Int("BP_ID")
+		if ok && okBP {
+			e.lines[hp] = append(e.lines[hp], bp)
+		}
+	}
+}
+
+// code returns the name for a code column, nil when it is empty or unknown.
+func (e *extractor) code(list string, row *Row, col string) *string {
+	name, ok := e.codes[list][codeKey(row.String(col))]
+	if !ok || name == "" {
+		return nil
+	}
+	return &name
+}
+
+// codeKey normalizes numeric codes so "01" and "1" match; most code lists
+// are numeric, TYPRAV uses letters.
+func codeKey(kod string) string {
+	if n, err := strconv.ParseInt(kod, 10, 64); err == nil {
+		return strconv.FormatInt(n, 10)
+	}
+	return kod
+}
+
+func (e *extractor) area(code int64) cuzk.CadastralArea {
+	return cuzk.CadastralArea{Code: int(code), Name: e.codes["KATUZE"][strconv.FormatInt(code, 10)]}
+}
+
+// sheet returns the LV number and cadastral area of the TEL a row refers to.
+func (e *extractor) sheet(row *Row) (number *string, area *cuzk.CadastralArea) {
+	id, ok := row.Int("TEL_ID")
+	if !ok {
+		return nil, nil
+	}
+	tel, ok := e.byID["TEL"][id]
+	if !ok {
+		return nil, nil
+	}
+	if n := tel.String("CISLO_TEL"); n != "" {
+		number = &n
+	}
+	if code, ok := tel.Int("KATUZE_KOD"); ok {
+		a := e.area(code)
+		area = &a
+	}
+	return number, area
+}
+
+func (e *extractor) parcels() []cuzk.Parcel {
+	refPoints := map[int64]cuzk.ReferencePoint{}
+	for _, row := range e.rows["OBDEBO"] {
+		id, ok := row.Int("PAR_ID")
+		y, okY := row.Float("SOURADNICE_Y")
+		x, okX := row.Float("SOURADNICE_X")
+		if ok && okY && okX {
+			refPoints[id] = cuzk.ReferencePoint{X: math.Abs(x), Y: math.Abs(y)}
+		}
+	}
+	centroids := e.boundaryCentroids()
+
+	out := make([]cuzk.Parcel, 0, len(e.rows["PAR"]))
+	for _, row := range e.rows["PAR"] {
+		id, _ := row.Int("ID")
+		base, _ := row.Int("KMENOVE_CISLO_PAR")
+		areaCode, _ := row.Int("KATUZE_KOD")
+		area, _ := row.Int("VYMERA_PARCELY")
+		p := cuzk.Parcel{
+			ID:            id,
+			BaseNumber:    int(base),
+			NumberingType: cuzk.NumberingLand,
+			CadastralArea: e.area(areaCode),
+			Area:          int(area),
+			LandType:      e.code("DRUPOZ", row, "DRUPOZ_KOD"),
+			UsageType:     e.code("ZPVYPA", row, "ZPVYPA_KOD"),
+		}
+		if n, _ := row.Int("DRUH_CISLOVANI_PAR"); n == numberingBuilding {
+			p.NumberingType = cuzk.NumberingBuilding
+		}
+		if sub, ok := row.Int("PODDELENI_CISLA_PAR"); ok && sub > 0 {
+			s := int(sub)
+			p.Subdivision = &s
+		}
+		p.OwnershipSheet, _ = e.sheet(row)
+		if rp, ok := refPoints[id]; ok {
+			p.ReferencePoint = &rp
+		} else if rp, ok := centroids[id]; ok {
+			p.ReferencePoint = &rp
+		}
+		out = append(out, p)
+	}
+	slices.SortFunc(out, func(a, b cuzk.Parcel) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// boundaryCentroids averages the vertices of the boundary lines (HP) of each parcel.
+func (e *extractor) boundaryCentroids() map[int64]cuzk.ReferencePoint {
+	vertices := map[int64]map[int64]point{}
+	for _, hp := range e.rows["HP"] {
+		id, _ := hp.Int("ID")
+		for _, col := range []string{"PAR_ID_1", "PAR_ID_2"} {
+			parID, ok := hp.Int(col)
+			if !ok {
+				continue
+			}
+			if vertices[parID] == nil {
+				vertices[parID] = map[int64]point{}
+			}
+			for _, pointID := range e.lines[id] {
+				if pt, ok := e.points[pointID]; ok {
+					vertices[parID][pointID] = pt
+				}
+			}
+		}
+	}
+	out := make(map[int64]cuzk.ReferencePoint, len(vertices))
+	for parID, pts := range vertices {
+		if len(pts) == 0 {
+			continue
+		}
+		var sum point
+		for _, pt := range pts {
+			sum.x += pt.x
+			sum.y += pt.y
+		}
+		n := float64(len(pts))
+		out[parID] = cuzk.ReferencePoint{X: round2(sum.x / n), Y: round2(sum.y / n)}
+	}
+	return out
+}
+
+func (e *extractor) neighbors() map[int64][]int64 {
+	out := map[int64][]int64{}
+	for _, hp := range e.rows["HP"] {
+		a, okA := hp.Int("PAR_ID_1")
+		b, okB := hp.Int("PAR_ID_2")
+		if !okA || !okB || a == b {
+			continue
+		}
+		out[a] = append(out[a], b)
+		out[b] = append(out[b], a)
+	}
+	for id, ids := range out {
+		slices.Sort(ids)
+		out[id] = slices.Compact(ids)
+	}
+	return out
+}
+
+func (e *extractor) buildings(parcels []cuzk.Parcel) []cuzk.Building {
+	parcelByID := make(map[int64]cuzk.Parcel, len(parcels))
+	for _, p := range parcels {
+		parcelByID[p.ID] = p
+	}
+	// The building plot is the parcel whose BUD_ID is the building.
+	plots := map[int64]cuzk.Parcel{}
+	for _, row := range e.rows["PAR"] {
+		budID, ok := row.Int("BUD_ID")
+		id, _ := row.Int("ID")
+		if _, seen := plots[budID]; ok && !seen {
+			plots[budID] = parcelByID[id]
+		}
+	}
+
+	out := make([]cuzk.Building, 0, len(e.rows["BUD"]))
+	for _, row := range e.rows["BUD"] {
+		id, _ := row.Int("ID")
+		b := cuzk.Building{
+			ID:            id,
+			MunicipalPart: e.code("CASOBC", row, "CAOBCE_KOD"),
+			UsageType:     e.code("ZPVYBU", row, "ZPVYBU_KOD"),
+		}
+		if t := e.code("TYPBUD", row, "TYPBUD_KOD"); t != nil {
+			b.BuildingType = *t
+		}
+		if n, ok := row.Int("CISLO_DOMOVNI"); ok {
+			no := int(n)
+			switch t, _ := row.Int("TYPBUD_KOD"); t {
+			case typeDescriptiveNo:
+				b.DescriptiveNo = &no
+			case typeEvidenceNo:
+				b.EvidenceNo = &no
+			}
+		}
+		var area *cuzk.CadastralArea
+		b.OwnershipSheet, area = e.sheet(row)
+		if plot, ok := plots[id]; ok {
+			label := parcelLabel(plot)
+			b.ParcelNumber = &label
+			b.CadastralArea = plot.CadastralArea
+		} else if area != nil {
+			b.CadastralArea = *area
+		}
+		out = append(out, b)
+	}
+	slices.SortFunc(out, func(a, b cuzk.Building) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+func (e *extractor) units(buildings []cuzk.Building) []cuzk.Unit {
+	buildingByID := make(map[int64]cuzk.Building, len(buildings))
+	for _, b := range buildings {
+		buildingByID[b.ID] = b
+	}
+
+	out := make([]cuzk.Unit, 0, len(e.rows["JED"]))
+	for _, row := range e.rows["JED"] {
+		id, _ := row.Int("ID")
+		u := cuzk.Unit{ID: id, UnitNumber: row.String("CISLO_JEDNOTKY")}
+		if t := cmp.Or(e.code("ZPVYJE", row, "ZPVYJE_KOD"), e.code("TYPJED", row, "TYPJED_KOD")); t != nil {
+			u.UnitType = *t
+		}
+		num, okNum := row.Int("PODIL_CITATEL")
+		den, okDen := row.Int("PODIL_JMENOVATEL")
+		if okNum && okDen {
+			u.CommonPartsShare = fmt.Sprintf("%d/%d", num, den)
+		}
+		u.OwnershipSheet, u.CadastralArea = e.sheet(row)
+		if budID, ok := row.Int("BUD_ID"); ok {
+			u.BuildingID = &budID
+			if b, ok := buildingByID[budID]; ok {
+				if no := cmp.Or(b.DescriptiveNo, b.EvidenceNo); no != nil {
+					u.UnitNumber = fmt.Sprintf("%d/%s", *no, u.UnitNumber)
+				}
+				if u.CadastralArea == nil {
+					area := b.CadastralArea
+					u.CadastralArea = &area
+				}
+			}
+		}
+		out = append(out, u)
+	}
+	slices.SortFunc(out, func(a, b cuzk.Unit) int { return cmp.Compare(a.ID, b.ID) })
+	return out
+}
+
+// ownershipSheets assembles an LV for every TEL from its VLA owners, the
+// already mapped objects recorded on it and the JPV rights burdening them.
+func (e *extractor) ownershipSheets(x *Extract) []cuzk.OwnershipSheet {
+	sheets := map[int64]*cuzk.OwnershipSheet{}
+	var order []int64
+	for _, tel := range e.rows["TEL"] {
+		id, _ := tel.Int("ID")
+		code, _ := tel.Int("KATUZE_KOD")
+		sheets[id] = &cuzk.OwnershipSheet{
+			Number:        tel.String("CISLO_TEL"),
+			CadastralArea: e.area(code),
+			Owners:        []cuzk.Owner{},
+			Parcels:       []cuzk.Parcel{},
+			Buildings:     []cuzk.Building{},
+			Units:         []cuzk.Unit{},
+			Encumbrances:  []cuzk.Encumbrance{},
+		}
+		order = append(order, id)
+	}
+	telOf := func(block string, id int64) *cuzk.OwnershipSheet {
+		row, ok := e.byID[block][id]
+		if !ok {
+			return nil
+		}
+		telID, _ := row.Int("TEL_ID")
+		return sheets[telID]
+	}
+	for _, p := range x.Parcels {
+		if lv := telOf("PAR", p.ID); lv != nil {
+			lv.Parcels = append(lv.Parcels, p)
+		}
+	}
+	for _, b := range x.Buildings {
+		if lv := telOf("BUD", b.ID); lv != nil {
+			lv.Buildings = append(lv.Buildings, b)
+		}
+	}
+	for _, u := range x.Units {
+		if lv := telOf("JED", u.ID); lv != nil {
+			lv.Units = append(lv.Units, u)
+		}
+	}
+	ownerSheets := map[int64][]*cuzk.OwnershipSheet{} // OPSUB ID -> sheets it owns
+	for _, vla := range e.rows["VLA"] {
+		telID, _ := vla.Int("TEL_ID")
+		lv, ok := sheets[telID]
+		if !ok {
+			continue
+		}
+		if owner, ok := e.owner(vla); ok {
+			lv.Owners = append(lv.Owners, owner)
+			subID, _ := vla.Int("OPSUB_ID")
+			ownerSheets[subID] = append(ownerSheets[subID], lv)
+		}
+	}
+
+	parcels := make(map[int64]cuzk.Parcel, len(x.Parcels))
+	for _, p := range x.Parcels {
+		parcels[p.ID] = p
+	}
+	for _, jpv := range e.rows["JPV"] {
+		// A right burdens a whole LV, an object recorded on one or an owner's share.
+		var burdened []*cuzk.OwnershipSheet
+		if id, ok := jpv.Int("TEL_ID_K"); ok {
+			burdened = append(burdened, sheets[id])
+		}
+		for block, col := range map[string]string{"PAR": "PAR_ID_K", "BUD": "BUD_ID_K", "JED": "JED_ID_K"} {
+			if id, ok := jpv.Int(col); ok {
+				burdened = append(burdened, telOf(block, id))
+			}
+		}
+		if id, ok := jpv.Int("OPSUB_ID_K"); ok {
+			burdened = append(burdened, ownerSheets[id]...)
+		}
+		burdened = slices.DeleteFunc(burdened, func(lv *cuzk.OwnershipSheet) bool { return lv == nil })
+		if len(burdened) == 0 {
+			continue
+		}
+
+		enc := e.encumbrance(jpv, parcels)
+		for i, lv := range burdened {
+			if !slices.Contains(burdened[:i], lv) {
+				lv.Encumbrances = append(lv.Encumbrances, enc)
+			}
+		}
+	}
+
+	out := make([]cuzk.OwnershipSheet, 0, len(order))
+	for _, id := range order {
+		out = append(out, *sheets[id])
+	}
+	return out
+}
+
+// encumbrance maps a JPV row. The beneficiary is a person or, for rights
+// attached to land, a parcel, as in "Parcela: p.č. 1521/2".
+func (e *extractor) encumbrance(jpv *Row, parcels map[int64]cuzk.Parcel) cuzk.Encumbrance {
+	var enc cuzk.Encumbrance
+	if kind := e.code("TYPRAV", jpv, "TYPRAV_KOD"); kind != nil {
+		enc.Type = lowerFirst(*kind)
+	}
+	enc.Description = cmp.Or(jpv.String("POPIS_PRAVNIHO_VZTAHU"), enc.Type)
+	if id, ok := jpv.Int("OPSUB_ID_PRO"); ok {
+		if sub, ok := e.byID["OPSUB"][id]; ok {
+			name := e.personName(sub)
+			if addr := address(sub); addr != nil {
+				name = joinNonEmpty(", ", name, *addr)
+			}
+			enc.Beneficiary = &name
+		}
+	} else if id, ok := jpv.Int("PAR_ID_PRO"); ok {
+		if p, ok := parcels[id]; ok {
+			label := "Parcela: " + parcelLabel(p)
+			enc.Beneficiary = &label
+		}
+	}
+	return enc
+}
+
+// owner maps a VLA row and the OPSUB it refers to.
+func (e *extractor) owner(vla *Row) (cuzk.Owner, bool) {
+	subID, _ := vla.Int("OPSUB_ID")
+	sub, ok := e.byID["OPSUB"][subID]
+	if !ok {
+		return cuzk.Owner{}, false
+	}
+	o := cuzk.Owner{Name: e.personName(sub), Address: address(sub)}
+	switch sub.String("OPSUB_TYPE") {
+	case personNatural:
+		o.Type = "fyzická osoba"
+	case personLegal:
+		o.Type = "právnická osoba"
+	case personSJM:
+		o.Type = "SJM"
+	}
+	if right := e.code("TYPRAV", vla, "TYPRAV_KOD"); right != nil {
+		o.Right = lowerFirst(*right)
+	}
+	num, okNum := vla.Int("PODIL_CITATEL")
+	den, okDen := vla.Int("PODIL_JMENOVATEL")
+	if okNum && okDen && den > 0 && num != den {
+		o.Share = &cuzk.Share{Numerator: int(num), Denominator: int(den)}
+	}
+	return o, true
+}
+
+// personName formats an OPSUB: the name of a company, "Ing. Jan Novák" for a
+// person and "SJM Jan Novák a Jana Nováková" for spouses.
+func (e *extractor) personName(sub *Row) string {
+	return e.name(sub, map[int64]bool{})
+}
+
+// name implements personName; seen holds the OPSUB IDs on the way, so SJM
+// rows referring to each other in a broken extract cannot recurse forever.
+func (e *extractor) name(sub *Row, seen map[int64]bool) string {
+	id, _ := sub.Int("ID")
+	seen[id] = true
+	if sub.String("OPSUB_TYPE") == personSJM {
+		var partners []string
+		for _, col := range []string{"ID_JE_1_PARTNER_BSM", "ID_JE_2_PARTNER_BSM"} {
+			pid, _ := sub.Int(col)
+			if p, ok := e.byID["OPSUB"][pid]; ok && !seen[pid] {
+				if name := e.name(p, seen); name != "" {
+					partners = append(partners, name)
+				}
+			}
+		}
+		if len(partners) > 0 {
+			return "SJM " + strings.Join(partners, " a ")
+		}
+	}
+	if name := sub.String("NAZEV"); name != "" && sub.String("OPSUB_TYPE") != personNatural {
+		return name
+	}
+	return joinNonEmpty(" ", sub.String("TITUL_PRED_JMENEM"), sub.String("JMENO"), sub.String("PRIJMENI"), sub.String("TITUL_ZA_JMENEM"))
+}
+
+// address formats "Street 12/3, 160 00 Praha" from the OPSUB address columns.
+func address(sub *Row) *string {
+	number := joinNonEmpty("/", sub.String("CISLO_DOMOVNI"), sub.String("CISLO_ORIENTACNI"))
+	street := joinNonEmpty(" ", cmp.Or(sub.String("NAZEV_ULICE"), sub.String("CAST_OBCE")), number)
+	a := joinNonEmpty(", ", street, joinNonEmpty(" ", formatPSC(sub.String("PSC")), sub.String("OBEC")))
+	if a == "" {
+		return nil
+	}
+	return &a
+}
+
+// formatPSC writes a postal code as "160 00".
+func formatPSC(psc string) string {
+	if len(psc) == 5 {
+		return psc[:3] + " " + psc[3:]
+	}
+	return psc
+}
+
+// lowerFirst turns "Vlastnické právo" into "vlastnické právo" as CUZK lists it.
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	return strings.Join(slices.DeleteFunc(parts, func(s string) bool { return s == "" }), sep)
+}
+
+// parcelLabel formats a parcel the way CUZK designates it, e.g. "st. 1520".
+func parcelLabel(p cuzk.Parcel) string {
+	n := cuzk.ParcelNumber{Base: p.BaseNumber, Type: p.NumberingType}
+	if p.Subdivision != nil {
+		n.Subdivision = *p.Subdivision
+	}
+	return n.String()
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,278 @@
+// Package vfk reads cadastral extracts in the VFK exchange format (výměnný
+// formát katastru) published by CUZK and maps them to the cuzk models.
+//
+// A VFK file is a sequence of lines: "&H" header entries, "&B" block
+// definitions listing the columns of a table, "&D" data rows of the most
+// recently defined block and a closing "&K". Values are separated by ";",
+// texts are quoted and a line ending in "¤" continues on the next one. Files
+// are encoded in windows-1250 unless the CODEPAGE header says ISO 8859-2.
+package vfk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // VFK times are Prague local time even in minimal containers
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// continuation ends a line that continues on the next one.
+const continuation = "¤"
+
+// maxLine bounds a single physical line; geometry rows can be long.
+const maxLine = 16 << 20
+
+// location is the time zone of VFK dates.
+var location = func() *time.Location {
+	loc, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// Column is a column of a block definition, e.g. "KMENOVE_CISLO_PAR N5".
+type Column struct {
+	Name string
+	Type byte // 'N' number, 'T' text, 'D' date
+}
+
+// Block is a table definition from a "&B" line.
+type Block struct {
+	Name    string
+	Columns []Column
+	index   map[string]int
+}
+
+// Row is a data row of a block. Accessors return the zero value for columns
+// the block does not define and for empty values, so readers of older VFK
+// versions with fewer columns keep working.
+type Row struct {
+	Block  *Block
+	values []string
+}
+
+// String returns a column's value as text.
+func (r *Row) String(col string) string {
+	i, ok := r.Block.index[col]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return r.values[i]
+}
+
+// IsNull reports whether a column is missing or empty.
+func (r *Row) IsNull(col string) bool {
+	return r.String(col) == ""
+}
+
+// Int returns a column's value as an integer; ok is false when it is empty
+// or not a number.
+func (r *Row) Int(col string) (n int64, ok bool) {
+	n, err := strconv.ParseInt(r.String(col), 10, 64)
+	return n, err == nil
+}
+
+// Float returns a column's value as a number.
+func (r *Row) Float(col string) (f float64, ok bool) {
+	f, err := strconv.ParseFloat(r.String(col), 64)
+	return f, err == nil
+}
+
+// Time returns a date column, formatted "02.01.2006 15:04:05" in VFK.
+func (r *Row) Time(col string) (t time.Time, ok bool) {
+	t, err := time.ParseInLocation("02.01.2006 15:04:05", r.String(col), location)
+	return t, err == nil
+}
+
+// Reader reads the rows of a VFK file one at a time.
+type Reader struct {
+	scanner *bufio.Scanner
+	decoder *encoding.Decoder
+	header  map[string][]string
+	blocks  map[string]*Block
+	line    int
+}
+
+// NewReader returns a Reader for a VFK file.
+func NewReader(r io.Reader) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64<<10), maxLine)
+	return &Reader{
+		scanner: s,
+		decoder: charmap.Windows1250.NewDecoder(),
+		header:  map[string][]string{},
+		blocks:  map[string]*Block{},
+	}
+}
+
+// Header returns the values of a header entry read so far, e.g. Header("VERZE").
+func (r *Reader) Header(name string) []string {
+	return r.header[name]
+}
+
+// Next returns the next data row, reading headers and block definitions on
+// the way. It returns io.EOF after the last row.
+func (r *Reader) Next() (*Row, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if len(line) < 2 || line[0] != '&' {
+			continue
+		}
+		kind, rest := line[1], line[2:]
+		switch kind {
+		case 'H':
+			name, values, err := splitRecord(rest)
+			if err != nil {
+				return nil, r.errorf("header: %w", err)
+			}
+			r.header[name] = values
+			if name == "CODEPAGE" && len(values) > 0 {
+				if err := r.setCodepage(values[0]); err != nil {
+					return nil, r.errorf("%w", err)
+				}
+			}
+		case 'B':
+			b, err := parseBlock(rest)
+			if err != nil {
+				return nil, r.errorf("block definition: %w", err)
+			}
+			r.blocks[b.Name] = b
+		case 'D':
+			name, values, err := splitRecord(rest)
+			if err != nil {
+				return nil, r.errorf("data: %w", err)
+			}
+			b, ok := r.blocks[name]
+			if !ok {
+				return nil, r.errorf("data row of undefined block %s", name)
+			}
+			return &Row{Block: b, values: values}, nil
+		case 'K':
+			return nil, io.EOF
+		}
+	}
+}
+
+// readLine returns the next logical line, decoded and with continuations joined.
+func (r *Reader) readLine() (string, error) {
+	var b strings.Builder
+	for {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return "", err
+			}
+			if b.Len() > 0 {
+				return b.String(), nil
+			}
+			return "", io.EOF
+		}
+		r.line++
+		text, err := r.decoder.String(strings.TrimRight(r.scanner.Text(), "\r"))
+		if err != nil {
+			return "", r.errorf("decode: %w", err)
+		}
+		if rest, ok := strings.CutSuffix(text, continuation); ok {
+			b.WriteString(rest)
+			continue
+		}
+		b.WriteString(text)
+		return b.String(), nil
+	}
+}
+
+// setCodepage switches the decoder for the lines after the CODEPAGE header.
+func (r *Reader) setCodepage(name string) error {
+	switch strings.ToUpper(name) {
+	case "EE8MSWIN1250", "WINDOWS-1250", "CP1250":
+		r.decoder = charmap.Windows1250.NewDecoder()
+	case "WE8ISO8859P2", "EE8ISO8859P2", "ISO-8859-2":
+		r.decoder = charmap.ISO8859_2.NewDecoder()
+	default:
+		return fmt.Errorf("unsupported codepage %q", name)
+	}
+	return nil
+}
+
+func (r *Reader) errorf(format string, args ...any) error {
+	return fmt.Errorf("vfk line %d: %w", r.line, fmt.Errorf(format, args...))
+}
+
+// parseBlock parses "PAR;ID N30;STAV_DAT N2;..." into a Block.
+func parseBlock(s string) (*Block, error) {
+	name, defs, _ := strings.Cut(s, ";")
+	if name == "" {
+		return nil, errors.New("missing block name")
+	}
+	b := &Block{Name: name, index: map[string]int{}}
+	for def := range strings.SplitSeq(defs, ";") {
+		col, typ, _ := strings.Cut(strings.TrimSpace(def), " ")
+		if col == "" {
+			continue
+		}
+		c := Column{Name: col}
+		if typ != "" {
+			c.Type = typ[0]
+		}
+		b.index[col] = len(b.Columns)
+		b.Columns = append(b.Columns, c)
+	}
+	return b, nil
+}
+
+// splitRecord splits `NAME;value;"text";...` into the name and its values.
+// Quotes are removed and a doubled quote inside a text stands for one quote.
+func splitRecord(s string) (name string, values []string, err error) {
+	name, rest, ok := strings.Cut(s, ";")
+	if name == "" {
+		return "", nil, errors.New("missing name")
+	}
+	if !ok {
+		return name, nil, nil
+	}
+	for {
+		var v string
+		if strings.HasPrefix(rest, `"`) {
+			var b strings.Builder
+			i := 1
+			for {
+				j := strings.IndexByte(rest[i:], '"')
+				if j < 0 {
+					return "", nil, errors.New("unterminated text")
+				}
+				b.WriteString(rest[i : i+j])
+				i += j + 1
+				if i < len(rest) && rest[i] == '"' {
+					b.WriteByte('"')
+					i++
+					continue
+				}
+				break
+			}
+			v, rest = b.String(), rest[i:]
+		} else {
+			end := strings.IndexByte(rest, ';')
+			if end < 0 {
+				end = len(rest)
+			}
+			v, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+		values = append(values, v)
+		if rest == "" {
+			return name, values, nil
+		}
+		if rest[0] != ';' {
+			return "", nil, fmt.Errorf("unexpected %q after value", rest[0])
+		}
+		rest = rest[1:]
+	}
+}
@@ -0,0 +1,153 @@
+package vfk
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// sample is a trimmed Dejvice extract: two parcels sharing a boundary, the
+// building on the first one, a flat in it, its owners and the rights
+// burdening them.
+const sample = `&HVERZE;"5.2"
+&HVYTVORENO;"18.10.2026 06:00:00"
+&HCODEPAGE;"EE8MSWIN1250"
+&BKATUZE;KOD N6;OBCE_KOD N6;NAZEV T48
+&DKATUZE;729272;554782;"Dejvice"
+&BDRUPOZ;KOD N2;NAZEV T60
+&DDRUPOZ;13;"zastavěná plocha a nádvoří"
+&DDRUPOZ;14;"ostatní plocha"
+&BZPVYPA;KOD N4;NAZEV T60
+&DZPVYPA;14;"ostatní komunikace"
+&BCASOBC;KOD N6;OBCE_KOD N6;NAZEV T48
+&DCASOBC;490067;554782;"Dejvice"
+&BTYPBUD;KOD N2;NAZEV T60
+&DTYPBUD;1;"budova s číslem popisným"
+&BZPVYBU;KOD N2;NAZEV T60
+&DZPVYBU;3;"bytový dům"
+&BZPVYJE;KOD N2;NAZEV T60
+&DZPVYJE;1;"byt"
+&BTYPRAV;KOD T4;NAZEV T100
+&DTYPRAV;"VL";"Vlastnické právo"
+&DTYPRAV;"VB";"Věcné břemeno chůze a jízdy"
+&DTYPRAV;"ZP";"Zástavní právo smluvní"
+&BTEL;ID N30;KATUZE_KOD N6;CISLO_TEL N6
+&DTEL;11;729272;1187
+&DTEL;12;729272;3120
+&BPAR;ID N30;STAV_DAT N2;DATUM_ZANIKU D;KATUZE_KOD N6;DRUH_CISLOVANI_PAR N1;KMENOVE_CISLO_PAR N5;PODDELENI_CISLA_PAR N3;DRUPOZ_KOD N2;ZPVYPA_KOD N4;VYMERA_PARCELY N9;TEL_ID N30;BUD_ID N30
+&DPAR;729272101;0;;729272;1;1520;;13;;612;11;729272501
+&DPAR;729272102;0;;729272;2;1521;2;14;14;¤
+85;11;
+&DPAR;729272109;0;"01.03.2024 00:00:00";729272;2;1521;3;14;;10;11;
+&BBUD;ID N30;DATUM_ZANIKU D;TYPBUD_KOD N2;CAOBCE_KOD N6;CISLO_DOMOVNI N4;ZPVYBU_KOD N2;TEL_ID N30
+&DBUD;729272501;;1;490067;1520;3;11
+&BJED;ID N30;DATUM_ZANIKU D;BUD_ID N30;CISLO_JEDNOTKY N4;ZPVYJE_KOD N2;PODIL_CITATEL N9;PODIL_JMENOVATEL N9;TEL_ID N30
+&DJED;729272801;;729272501;1;1;742;10534;12
+&BOPSUB;ID N30;OPSUB_TYPE T3;NAZEV T255;JMENO T100;PRIJMENI T100;TITUL_PRED_JMENEM T35;NAZEV_ULICE T48;CISLO_DOMOVNI N4;CISLO_ORIENTACNI T4;OBEC T48;PSC T5;ID_JE_1_PARTNER_BSM N30;ID_JE_2_PARTNER_BSM N30
+&DOPSUB;1;"OPO";"Bytové družstvo ""Dejvice""";;;;"Dejvická";1520;"12";"Praha";"16000";;
+&DOPSUB;2;"OFO";;"Jan";"Novák";"Ing.";;;;;;;
+&DOPSUB;3;"OFO";;"Jana";"Nováková";;;;;;;;
+&DOPSUB;4;"BSM";;;;;;;;;;2;3
+&DOPSUB;5;"BSM";;;;;;;;;;5;
+&BVLA;ID N30;DATUM_ZANIKU D;OPSUB_ID N30;TEL_ID N30;TYPRAV_KOD T4;PODIL_CITATEL N9;PODIL_JMENOVATEL N9
+&DVLA;1;;1;11;"VL";;
+&DVLA;2;;4;12;"VL";1;2
+&DVLA;3;;2;12;"VL";1;2
+&DVLA;4;;5;11;"VL";;
+&BJPV;ID N30;DATUM_ZANIKU D;TYPRAV_KOD T4;POPIS_PRAVNIHO_VZTAHU T255;TEL_ID_K N30;PAR_ID_K N30;JED_ID_K N30;OPSUB_ID_K N30;PAR_ID_PRO N30;OPSUB_ID_PRO N30
+&DJPV;1;;"VB";"Oprávnění pro vlastníka parcely 1521/2";;729272101;;;729272102;
+&DJPV;2;;"ZP";"Pohledávka ve výši 4 500 000 Kč";12;;729272801;2;;1
+&DJPV;3;"01.03.2024 00:00:00";"ZP";;11;;;;;1
+&BOBDEBO;ID N30;PAR_ID N30;BUD_ID N30;SOURADNICE_Y N10.2;SOURADNICE_X N10.2
+&DOBDEBO;1;729272101;;744800.86;1041306.07
+&BSOBR;ID N30;SOURADNICE_Y N10.2;SOURADNICE_X N10.2
+&DSOBR;1;744810.00;1041300.00
+&DSOBR;2;744830.00;1041300.00
+&DSOBR;3;744830.00;1041320.00
+&DSOBR;4;744810.00;1041320.00
+&BHP;ID N30;PAR_ID_1 N30;PAR_ID_2 N30
+&DHP;50;729272101;729272102
+&DHP;51;729272102;
+&BSBP;ID N30;BP_ID N30;PORADOVE_CISLO_BODU N4;HP_ID N30
+&DSBP;1;1;1;50
+&DSBP;2;2;2;50
+&DSBP;3;2;1;51
+&DSBP;4;3;2;51
+&DSBP;5;4;3;51
+&DSBP;6;1;4;51
+&BSBM;ID N30;BP_ID N30;PORADOVE_CISLO_BODU N4;DPM_ID N30
+&DSBM;1;3;1;70
+&K
+`
+
+func TestReadMapsExtract(t *testing.T) {
+	encoded, err := charmap.Windows1250.NewEncoder().String(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := Read(bytes.NewReader([]byte(encoded)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(x.Parcels) != 2 {
+		t.Fatalf("expected the cancelled parcel to be skipped, got %+v", x.Parcels)
+	}
+	plot, road := x.Parcels[0], x.Parcels[1]
+	if plot.NumberingType != cuzk.NumberingBuilding || plot.CadastralArea.Name != "Dejvice" ||
+		*plot.LandType != "zastavěná plocha a nádvoří" || *plot.OwnershipSheet != "1187" ||
+		plot.ReferencePoint.X != 1041306.07 {
+		t.Errorf("building plot: %+v", plot)
+	}
+	// The continued line still yields the area; the point comes from the boundary.
+	if road.Area != 85 || *road.Subdivision != 2 || *road.UsageType != "ostatní komunikace" ||
+		road.ReferencePoint == nil || road.ReferencePoint.X != 1041310 || road.ReferencePoint.Y != 744820 {
+		t.Errorf("road: %+v %+v", road, road.ReferencePoint)
+	}
+	if n := x.Neighbors[729272102]; len(n) != 1 || n[0] != 729272101 {
+		t.Errorf("neighbors: %v", x.Neighbors)
+	}
+
+	b := x.Buildings[0]
+	if *b.DescriptiveNo != 1520 || *b.ParcelNumber != "st. 1520" || *b.MunicipalPart != "Dejvice" ||
+		b.BuildingType != "budova s číslem popisným" || b.CadastralArea.Code != 729272 {
+		t.Errorf("building: %+v", b)
+	}
+	u := x.Units[0]
+	if u.UnitNumber != "1520/1" || u.UnitType != "byt" || u.CommonPartsShare != "742/10534" ||
+		*u.BuildingID != b.ID || *u.OwnershipSheet != "3120" {
+		t.Errorf("unit: %+v", u)
+	}
+
+	lv := x.OwnershipSheets[0]
+	if lv.Number != "1187" || len(lv.Parcels) != 2 || len(lv.Buildings) != 1 ||
+		lv.Owners[0].Name != `Bytové družstvo "Dejvice"` || lv.Owners[0].Share != nil ||
+		*lv.Owners[0].Address != "Dejvická 1520/12, 160 00 Praha" {
+		t.Errorf("LV 1187: %+v", lv)
+	}
+	// The self-referencing SJM is named without recursing forever.
+	if len(lv.Owners) != 2 || lv.Owners[1].Type != "SJM" {
+		t.Errorf("LV 1187 owners: %+v", lv.Owners)
+	}
+	if len(lv.Encumbrances) != 1 || lv.Encumbrances[0].Type != "věcné břemeno chůze a jízdy" ||
+		*lv.Encumbrances[0].Beneficiary != "Parcela: p.č. 1521/2" {
+		t.Errorf("LV 1187 encumbrances: %+v", lv.Encumbrances)
+	}
+	owners := x.OwnershipSheets[1].Owners
+	if owners[0].Name != "SJM Ing. Jan Novák a Jana Nováková" || owners[0].Type != "SJM" ||
+		owners[0].Right != "vlastnické právo" || *owners[0].Share != (cuzk.Share{Numerator: 1, Denominator: 2}) ||
+		owners[1].Name != "Ing. Jan Novák" {
+		t.Errorf("LV 3120 owners: %+v", owners)
+	}
+	// Burdening the LV, a unit on it and an owner still lists the lien once.
+	if enc := x.OwnershipSheets[1].Encumbrances; len(enc) != 1 || enc[0].Description != "Pohledávka ve výši 4 500 000 Kč" ||
+		*enc[0].Beneficiary != `Bytové družstvo "Dejvice", Dejvická 1520/12, 160 00 Praha` {
+		t.Errorf("LV 3120 encumbrances: %+v", enc)
+	}
+	if x.Rows["SBM"] != 1 || x.Rows["PAR"] != 3 {
+		t.Errorf("row counts: %v", x.Rows)
+	}
+}
@@ -0,0 +1,207 @@
+// Package warmer pre-populates the parcel caches for configured cadastral areas
+// so the first requests of the day do not wait on the CUZK rate limit.
+package warmer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// maxConsecutiveErrors aborts a run (keeping the checkpoint) when CUZK keeps failing.
+const maxConsecutiveErrors = 10
+
+// ErrRunning is returned by Run when another run is in progress.
+var ErrRunning = errors.New("warmer: run already in progress")
+
+// Target populates cache entries; implemented by handler.ParcelHandler.
+type Target interface {
+	// WarmSearch caches a parcel base number search and returns the parcel IDs found.
+	WarmSearch(ctx context.Context, areaCode int, base int) ([]int64, error)
+	// WarmParcel caches a parcel detail.
+	WarmParcel(ctx context.Context, id int64) error
+}
+
+// Plan is what a run walks: every parcel number From..To in every area.
+type Plan struct {
+	Areas []int `json:"areas"`
+	From  int   `json:"from"`
+	To    int   `json:"to"`
+}
+
+// Checkpoint is the persisted progress of a run.
+type Checkpoint struct {
+	Plan       Plan       `json:"plan"`
+	StartedAt  time.Time  `json:"startedAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	AreaIndex  int        `json:"areaIndex"` // index into Plan.Areas
+	Number     int        `json:"number"`    // next parcel number to warm
+	Searches   int        `json:"searches"`
+	Parcels    int        `json:"parcels"`
+	Errors     int        `json:"errors"`
+}
+
+func (c *Checkpoint) finished() bool {
+	return c.FinishedAt != nil
+}
+
+// StateStore persists the checkpoint between runs.
+type StateStore interface {
+	// Load returns the last checkpoint, or nil if there is none.
+	Load() (*Checkpoint, error)
+	Save(cp *Checkpoint) error
+}
+
+// Warmer walks a Plan and warms the cache through a Target.
+type Warmer struct {
+	target  Target
+	state   StateStore
+	plan    Plan
+	running atomic.Bool
+}
+
+// New creates a Warmer.
+func New(target Target, state StateStore, plan Plan) *Warmer {
+	return &Warmer{target: target, state: state, plan: plan}
+}
+
+// Run warms the cache, resuming an interrupted run with the same plan.
+// Upstream calls are queued at cuzk.PriorityBulk so interactive requests go first.
+// When ctx ends the checkpoint is saved and ctx.Err() is returned.
+func (w *Warmer) Run(ctx context.Context) error {
+	if !w.running.CompareAndSwap(false, true) {
+		return ErrRunning
+	}
+	defer w.running.Store(false)
+	ctx = cuzk.WithPriority(ctx, cuzk.PriorityBulk)
+
+	cp, err := w.state.Load()
+	if err != nil {
+		return fmt.Errorf("load checkpoint: %w", err)
+	}
+	if cp == nil || cp.finished() || !samePlan(cp.Plan, w.plan) {
+		cp = &Checkpoint{Plan: w.plan, StartedAt: time.Now(), Number: w.plan.From}
+		slog.Info("cache warming started", "areas", len(w.plan.Areas), "from", w.plan.From, "to", w.plan.To)
+	} else {
+		slog.Info("cache warming resumed", "area", w.plan.Areas[cp.AreaIndex], "number", cp.Number)
+	}
+
+	consecutiveErrors := 0
+	for cp.AreaIndex < len(w.plan.Areas) {
+		area := w.plan.Areas[cp.AreaIndex]
+		for cp.Number <= w.plan.To {
+			if err := ctx.Err(); err != nil {
+				return w.save(cp, err)
+			}
+
+			err := w.warmNumber(ctx, cp, area, cp.Number)
+			switch {
+			case err == nil:
+				consecutiveErrors = 0
+			case ctx.Err() != nil:
+				return w.save(cp, ctx.Err())
+			case cuzk.IsUnauthorized(err):
+				return w.save(cp, fmt.Errorf("aborting, CUZK rejected the API key: %w", err))
+			case errors.Is(err, cuzk.ErrQuotaExhausted):
+				return w.save(cp, fmt.Errorf("aborting, daily quota used up: %w", err))
+			default:
+				cp.Errors++
+				consecutiveErrors++
+				slog.Warn("cache warming error", "area", area, "number", cp.Number, "error", err)
+				if consecutiveErrors >= maxConsecutiveErrors {
+					return w.save(cp, fmt.Errorf("aborting after %d consecutive errors: %w", consecutiveErrors, err))
+				}
+			}
+
+			cp.Number++
+			if err := w.save(cp, nil); err != nil {
+				return err
+			}
+		}
+		cp.AreaIndex++
+		cp.Number = w.plan.From
+	}
+
+	now := time.Now()
+	cp.FinishedAt = &now
+	slog.Info("cache warming finished",
+		"searches", cp.Searches,
+		"parcels", cp.Parcels,
+		"errors", cp.Errors,
+		"duration", now.Sub(cp.StartedAt).Round(time.Second).String(),
+	)
+	return w.save(cp, nil)
+}
+
+// warmNumber caches the search for one parcel number and the detail of every parcel found.
+func (w *Warmer) warmNumber(ctx context.Context, cp *Checkpoint, area, number int) error {
+	ids, err := w.target.WarmSearch(ctx, area, number)
+	if err != nil {
+		return err
+	}
+	cp.Searches++
+
+	for _, id := range ids {
+		if err := w.target.WarmParcel(ctx, id); err != nil && !cuzk.IsNotFound(err) {
+			return err
+		}
+		cp.Parcels++
+	}
+	return nil
+}
+
+// save persists cp and returns runErr, or the save error if there is no runErr.
+func (w *Warmer) save(cp *Checkpoint, runErr error) error {
+	cp.UpdatedAt = time.Now()
+	if err := w.state.Save(cp); err != nil {
+		slog.Error("save warmer checkpoint", "error", err)
+		if runErr == nil {
+			return fmt.Errorf("save checkpoint: %w", err)
+		}
+	}
+	return runErr
+}
+
+// Schedule runs the warmer every day at the given local time ("HH:MM") until ctx ends.
+func (w *Warmer) Schedule(ctx context.Context, at string) error {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("invalid warm time %q: %w", at, err)
+	}
+
+	go func() {
+		for {
+			next := nextRun(time.Now(), clock.Hour(), clock.Minute())
+			slog.Info("cache warming scheduled", "at", next.Format(time.RFC3339))
+			select {
+			case <-time.After(time.Until(next)):
+			case <-ctx.Done():
+				return
+			}
+			if err := w.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("scheduled cache warming failed", "error", err)
+			}
+		}
+	}()
+	return nil
+}
+
+// nextRun returns the next occurrence of hour:minute after now.
+func nextRun(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func samePlan(a, b Plan) bool {
+	return a.From == b.From && a.To == b.To && slices.Equal(a.Areas, b.Areas)
+}
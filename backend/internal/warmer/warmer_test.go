@@ -0,0 +1,73 @@
+package warmer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type fakeTarget struct {
+	searches []int
+	parcels  []int64
+	// cancel is called after this many searches, simulating an interruption.
+	cancelAfter int
+	cancel      context.CancelFunc
+}
+
+func (f *fakeTarget) WarmSearch(ctx context.Context, areaCode int, number int) ([]int64, error) {
+	f.searches = append(f.searches, number)
+	if f.cancel != nil && len(f.searches) == f.cancelAfter {
+		f.cancel()
+	}
+	return []int64{int64(areaCode)*1000 + int64(len(f.searches))}, nil
+}
+
+func (f *fakeTarget) WarmParcel(ctx context.Context, id int64) error {
+	f.parcels = append(f.parcels, id)
+	return nil
+}
+
+func TestRunResumesFromCheckpoint(t *testing.T) {
+	state := NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+	plan := Plan{Areas: []int{729272, 730122}, From: 1, To: 3}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := &fakeTarget{cancelAfter: 4, cancel: cancel}
+	err := New(first, state, plan).Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("first run: got %v, want context.Canceled", err)
+	}
+
+	cp, err := state.Load()
+	if err != nil || cp == nil {
+		t.Fatalf("load checkpoint: %v, %v", cp, err)
+	}
+	if cp.AreaIndex != 1 || cp.Number != 2 || cp.finished() {
+		t.Fatalf("checkpoint = area %d number %d, want area 1 number 2", cp.AreaIndex, cp.Number)
+	}
+
+	second := &fakeTarget{}
+	if err := New(second, state, plan).Run(context.Background()); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if got := len(second.searches); got != 2 {
+		t.Errorf("second run searched %d numbers, want 2 (%v)", got, second.searches)
+	}
+
+	cp, _ = state.Load()
+	if !cp.finished() || cp.Searches != 6 {
+		t.Errorf("final checkpoint finished=%v searches=%d, want true, 6", cp.finished(), cp.Searches)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	now := time.Date(2025, 3, 10, 5, 0, 0, 0, time.UTC)
+	if got := nextRun(now, 4, 30); !got.Equal(time.Date(2025, 3, 11, 4, 30, 0, 0, time.UTC)) {
+		t.Errorf("nextRun before now = %v", got)
+	}
+	if got := nextRun(now, 6, 0); !got.Equal(time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("nextRun later today = %v", got)
+	}
+}
@@ -0,0 +1,214 @@
+package watchlist
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxEvents bounds the event log of a FileStore; the oldest events are dropped first.
+const maxEvents = 5000
+
+// fileState is the content of the FileStore file.
+type fileState struct {
+	Items     map[string]Item     `json:"items"`
+	Snapshots map[string]Snapshot `json:"snapshots"`
+	Events    []Event             `json:"events"`
+	LastSeq   int64               `json:"lastSeq"`
+}
+
+// FileStore keeps the watchlist in a JSON file, rewritten on every change.
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	state  *fileState // loaded on first use
+	loaded bool
+}
+
+// NewFileStore creates a FileStore backed by path; the file is created on the first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Items() ([]Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(st.Items))
+	for _, it := range st.Items {
+		items = append(items, it)
+	}
+	slices.SortFunc(items, func(a, b Item) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return items, nil
+}
+
+func (s *FileStore) Item(ref Ref) (*Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	it, ok := st.Items[ref.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &it, nil
+}
+
+func (s *FileStore) PutItem(item Item) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	st.Items[item.Ref.String()] = item
+	return s.save()
+}
+
+func (s *FileStore) UpdateItem(ref Ref, fn func(*Item)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	it, ok := st.Items[ref.String()]
+	if !ok {
+		return ErrNotFound
+	}
+	fn(&it)
+	st.Items[ref.String()] = it
+	return s.save()
+}
+
+func (s *FileStore) DeleteItem(ref Ref) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := st.Items[ref.String()]; !ok {
+		return ErrNotFound
+	}
+	delete(st.Items, ref.String())
+	delete(st.Snapshots, ref.String())
+	return s.save()
+}
+
+func (s *FileStore) Snapshot(ref Ref) (*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	snap, ok := st.Snapshots[ref.String()]
+	if !ok {
+		return nil, nil
+	}
+	return &snap, nil
+}
+
+func (s *FileStore) PutSnapshot(ref Ref, snap Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	st.Snapshots[ref.String()] = snap
+	return s.save()
+}
+
+func (s *FileStore) AppendEvents(events []Event) ([]Event, error) {
+	if len(events) == 0 {
+		return events, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := slices.Clone(events)
+	for i := range out {
+		st.LastSeq++
+		out[i].Seq = st.LastSeq
+	}
+	st.Events = append(st.Events, out...)
+	if n := len(st.Events) - maxEvents; n > 0 {
+		st.Events = slices.Delete(st.Events, 0, n)
+	}
+	return out, s.save()
+}
+
+func (s *FileStore) Events(q EventQuery) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := []Event{}
+	for _, e := range st.Events {
+		if e.Seq > q.AfterSeq && (q.Ref == nil || e.Ref == *q.Ref) {
+			out = append(out, e)
+		}
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// load reads the file once. Must be called with s.mu held.
+func (s *FileStore) load() (*fileState, error) {
+	if s.loaded {
+		return s.state, nil
+	}
+	st := &fileState{}
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, err
+		}
+	}
+	if st.Items == nil {
+		st.Items = map[string]Item{}
+	}
+	if st.Snapshots == nil {
+		st.Snapshots = map[string]Snapshot{}
+	}
+	s.state, s.loaded = st, true
+	return st, nil
+}
+
+// save writes the state atomically via a temp file and rename. Must be called with s.mu held.
+func (s *FileStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,315 @@
+package watchlist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+// ErrRunning is returned by Run when another run is in progress.
+var ErrRunning = errors.New("watchlist: run already in progress")
+
+// sealStatus is the proceeding status CUZK reports while a seal (plomba) marks the affected objects.
+const sealStatus = "plomba"
+
+// Source fetches watched objects; implemented by *cuzk.Client, so checks share
+// its rate limiter, circuit breaker and API key quota with everything else.
+type Source interface {
+	GetParcel(ctx context.Context, id int64) (*cuzk.Parcel, error)
+	GetBuilding(ctx context.Context, id int64) (*cuzk.Building, error)
+	GetUnit(ctx context.Context, id int64) (*cuzk.Unit, error)
+	GetParcelProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error)
+	GetBuildingProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error)
+	GetUnitProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error)
+}
+
+// Notifier receives recorded events, e.g. to push them to webhooks. Notify
+// must not block on slow consumers.
+type Notifier interface {
+	Notify(events []Event)
+}
+
+// Monitor re-fetches watched objects and records the differences as events.
+type Monitor struct {
+	source    Source
+	store     Store
+	notifiers []Notifier
+	running   atomic.Bool
+}
+
+// NewMonitor creates a Monitor that passes recorded events to notifiers.
+func NewMonitor(source Source, store Store, notifiers ...Notifier) *Monitor {
+	return &Monitor{source: source, store: store, notifiers: notifiers}
+}
+
+// Run checks every watched object once. Upstream calls are queued at
+// cuzk.PriorityBackground so interactive requests go first. A failing object
+// is recorded in its LastError and does not stop the run, except when CUZK
+// rejects the API key or the quota is used up.
+func (m *Monitor) Run(ctx context.Context) error {
+	if !m.running.CompareAndSwap(false, true) {
+		return ErrRunning
+	}
+	defer m.running.Store(false)
+	ctx = cuzk.WithPriority(ctx, cuzk.PriorityBackground)
+
+	items, err := m.store.Items()
+	if err != nil {
+		return fmt.Errorf("load watchlist: %w", err)
+	}
+
+	var changes, failures int
+	for _, it := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		events, err := m.Check(ctx, it.Ref)
+		switch {
+		case err == nil:
+			changes += len(events)
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case cuzk.IsUnauthorized(err):
+			return fmt.Errorf("aborting, CUZK rejected the API key: %w", err)
+		case errors.Is(err, cuzk.ErrQuotaExhausted):
+			return fmt.Errorf("aborting, daily quota used up: %w", err)
+		default:
+			failures++
+			slog.Warn("watchlist check failed", "object", it.Ref.String(), "error", err)
+		}
+	}
+	slog.Info("watchlist checked", "objects", len(items), "changes", changes, "errors", failures)
+	return nil
+}
+
+// Check fetches one watched object, compares it with the last snapshot and
+// records the resulting events. The first check only records the snapshot.
+func (m *Monitor) Check(ctx context.Context, ref Ref) ([]Event, error) {
+	if _, err := m.store.Item(ref); err != nil {
+		return nil, err
+	}
+	prev, err := m.store.Snapshot(ref)
+	if err != nil {
+		return nil, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	next, err := m.fetch(ctx, ref)
+	if err != nil {
+		if ctx.Err() == nil {
+			m.recordCheck(ref, nil, err)
+		}
+		return nil, err
+	}
+
+	var events []Event
+	if prev != nil {
+		events = diff(ref, prev, next)
+	}
+	if events, err = m.store.AppendEvents(events); err != nil {
+		return nil, fmt.Errorf("record events: %w", err)
+	}
+	if err := m.store.PutSnapshot(ref, *next); err != nil {
+		return nil, fmt.Errorf("save snapshot: %w", err)
+	}
+	m.recordCheck(ref, next, nil)
+	for _, e := range events {
+		slog.Info("watched object changed", "object", ref.String(), "change", e.Change, "proceeding", e.ProceedingID)
+	}
+	if len(events) > 0 {
+		for _, n := range m.notifiers {
+			n.Notify(events)
+		}
+	}
+	return events, nil
+}
+
+// recordCheck stores the outcome of a check on the item, unless it was unwatched meanwhile.
+func (m *Monitor) recordCheck(ref Ref, snap *Snapshot, checkErr error) {
+	err := m.store.UpdateItem(ref, func(it *Item) {
+		now := time.Now()
+		it.LastCheckedAt = &now
+		if checkErr != nil {
+			it.LastError = checkErr.Error()
+			return
+		}
+		it.LastError = ""
+		it.Sealed = sealed(snap.Proceedings)
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		slog.Error("save watchlist item", "object", ref.String(), "error", err)
+	}
+}
+
+// fetch builds a snapshot of ref from CUZK. A 404 for the object yields a snapshot without Object.
+func (m *Monitor) fetch(ctx context.Context, ref Ref) (*Snapshot, error) {
+	var (
+		object      any
+		err         error
+		proceedings func(context.Context, int64) (*cuzk.ProceedingSearchResponse, error)
+	)
+	switch ref.Kind {
+	case KindParcel:
+		object, err = m.source.GetParcel(ctx, ref.ObjectID)
+		proceedings = m.source.GetParcelProceedings
+	case KindBuilding:
+		object, err = m.source.GetBuilding(ctx, ref.ObjectID)
+		proceedings = m.source.GetBuildingProceedings
+	case KindUnit:
+		object, err = m.source.GetUnit(ctx, ref.ObjectID)
+		proceedings = m.source.GetUnitProceedings
+	default:
+		return nil, fmt.Errorf("unknown kind %q", ref.Kind)
+	}
+
+	snap := &Snapshot{CheckedAt: time.Now(), Proceedings: map[int64]string{}}
+	if cuzk.IsNotFound(err) {
+		return snap, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if snap.Object, err = json.Marshal(object); err != nil {
+		return nil, err
+	}
+
+	resp, err := proceedings(ctx, ref.ObjectID)
+	if err != nil && !cuzk.IsNotFound(err) {
+		return nil, err
+	}
+	if resp != nil {
+		for _, p := range resp.Proceedings {
+			snap.Proceedings[p.ID] = p.Status
+		}
+	}
+	return snap, nil
+}
+
+// diff returns the events that lead from prev to next.
+func diff(ref Ref, prev, next *Snapshot) []Event {
+	area := cadastralArea(next.Object)
+	if area == 0 {
+		area = cadastralArea(prev.Object)
+	}
+	event := func(change ChangeKind) Event {
+		return Event{Ref: ref, At: next.CheckedAt, CadastralArea: area, Change: change}
+	}
+
+	var events []Event
+	switch {
+	case prev.Object != nil && next.Object == nil:
+		events = append(events, event(ChangeObjectRemoved))
+	case prev.Object != nil:
+		if fields := changedFields(prev.Object, next.Object); len(fields) > 0 {
+			e := event(ChangeAttributes)
+			e.Fields = fields
+			events = append(events, e)
+		}
+	}
+
+	for _, id := range sortedIDs(next.Proceedings) {
+		status := next.Proceedings[id]
+		old, seen := prev.Proceedings[id]
+		switch {
+		case !seen:
+			e := event(ChangeProceedingStarted)
+			e.ProceedingID, e.To = id, status
+			events = append(events, e)
+		case old != status:
+			e := event(ChangeProceedingStatus)
+			e.ProceedingID, e.From, e.To = id, old, status
+			events = append(events, e)
+		}
+	}
+	for _, id := range sortedIDs(prev.Proceedings) {
+		if _, ok := next.Proceedings[id]; !ok {
+			e := event(ChangeProceedingClosed)
+			e.ProceedingID, e.From = id, prev.Proceedings[id]
+			events = append(events, e)
+		}
+	}
+
+	switch was, is := sealed(prev.Proceedings), sealed(next.Proceedings); {
+	case !was && is:
+		events = append(events, event(ChangeSealAdded))
+	case was && !is:
+		events = append(events, event(ChangeSealRemoved))
+	}
+	return events
+}
+
+// changedFields compares two CUZK objects attribute by attribute.
+func changedFields(a, b json.RawMessage) []string {
+	var ma, mb map[string]json.RawMessage
+	if json.Unmarshal(a, &ma) != nil || json.Unmarshal(b, &mb) != nil {
+		return nil
+	}
+	var fields []string
+	for k, v := range mb {
+		if !bytes.Equal(ma[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range ma {
+		if _, ok := mb[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// cadastralArea returns the cadastral area code of a CUZK object, 0 if unknown.
+func cadastralArea(object json.RawMessage) int {
+	var v struct {
+		Area *cuzk.CadastralArea `json:"katastralniUzemi"`
+	}
+	if object == nil || json.Unmarshal(object, &v) != nil || v.Area == nil {
+		return 0
+	}
+	return v.Area.Code
+}
+
+func sealed(proceedings map[int64]string) bool {
+	for _, status := range proceedings {
+		if status == sealStatus {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIDs(m map[int64]string) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Schedule runs the monitor every interval until ctx ends.
+func (m *Monitor) Schedule(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		slog.Info("watchlist monitoring scheduled", "interval", interval.String())
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			if err := m.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("watchlist run failed", "error", err)
+			}
+		}
+	}()
+}
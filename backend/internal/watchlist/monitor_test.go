@@ -0,0 +1,90 @@
+package watchlist
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/cuzk"
+)
+
+type fakeSource struct {
+	parcel      cuzk.Parcel
+	proceedings []cuzk.Proceeding
+}
+
+func (f *fakeSource) GetParcel(ctx context.Context, id int64) (*cuzk.Parcel, error) {
+	p := f.parcel
+	return &p, nil
+}
+
+func (f *fakeSource) GetParcelProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error) {
+	return &cuzk.ProceedingSearchResponse{Proceedings: slices.Clone(f.proceedings), Total: len(f.proceedings)}, nil
+}
+
+func (f *fakeSource) GetBuilding(ctx context.Context, id int64) (*cuzk.Building, error) {
+	return nil, &cuzk.APIError{StatusCode: 404}
+}
+
+func (f *fakeSource) GetUnit(ctx context.Context, id int64) (*cuzk.Unit, error) {
+	return nil, &cuzk.APIError{StatusCode: 404}
+}
+
+func (f *fakeSource) GetBuildingProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error) {
+	return &cuzk.ProceedingSearchResponse{}, nil
+}
+
+func (f *fakeSource) GetUnitProceedings(ctx context.Context, id int64) (*cuzk.ProceedingSearchResponse, error) {
+	return &cuzk.ProceedingSearchResponse{}, nil
+}
+
+func TestMonitorRecordsSealAndNewProceeding(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "watchlist.json")
+	store := NewFileStore(path)
+	ref := Ref{Kind: KindParcel, ObjectID: 729272101}
+	if err := store.PutItem(Item{Ref: ref, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	src := &fakeSource{
+		parcel:      cuzk.Parcel{ID: 729272101, BaseNumber: 1520, CadastralArea: cuzk.CadastralArea{Code: 729272}, Area: 412},
+		proceedings: []cuzk.Proceeding{{ID: 9101001, Status: "zapsáno"}},
+	}
+	m := NewMonitor(src, store)
+	if events, err := m.Check(ctx, ref); err != nil || len(events) != 0 {
+		t.Fatalf("first check should only record a baseline, got %+v, %v", events, err)
+	}
+
+	src.parcel.Area = 398
+	src.proceedings = append(src.proceedings, cuzk.Proceeding{ID: 9101002, Status: "plomba"})
+	if err := m.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := store.Events(EventQuery{Ref: &ref})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []ChangeKind
+	for _, e := range events {
+		changes = append(changes, e.Change)
+		if e.CadastralArea != 729272 {
+			t.Errorf("event %s without cadastral area", e.Change)
+		}
+	}
+	want := []ChangeKind{ChangeAttributes, ChangeProceedingStarted, ChangeSealAdded}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("changes = %v, want %v", changes, want)
+	}
+	if !slices.Equal(events[0].Fields, []string{"vymera"}) || events[1].ProceedingID != 9101002 {
+		t.Errorf("unexpected events %+v", events)
+	}
+
+	item, _ := NewFileStore(path).Item(ref)
+	if item == nil || !item.Sealed || item.LastCheckedAt == nil {
+		t.Errorf("reloaded item not marked sealed: %+v", item)
+	}
+}
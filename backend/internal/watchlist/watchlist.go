@@ -0,0 +1,129 @@
+// Package watchlist tracks selected parcels, buildings and units and records
+// change events, such as a seal (plomba) or a new proceeding, when periodic
+// re-fetches from CUZK differ from the last snapshot.
+package watchlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned by a Store for an object that is not watched.
+var ErrNotFound = errors.New("watchlist: object not watched")
+
+// Kind is the type of a watched object.
+type Kind string
+
+const (
+	KindParcel   Kind = "parcel"
+	KindBuilding Kind = "building"
+	KindUnit     Kind = "unit"
+)
+
+// ParseKind validates a kind from user input.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case KindParcel, KindBuilding, KindUnit:
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown kind %q, must be parcel, building or unit", s)
+}
+
+// Ref identifies a watched object.
+type Ref struct {
+	Kind     Kind  `json:"kind"`
+	ObjectID int64 `json:"objectId"`
+}
+
+func (r Ref) String() string {
+	return fmt.Sprintf("%s:%d", r.Kind, r.ObjectID)
+}
+
+// Item is an object on the watchlist with the outcome of its last check.
+type Item struct {
+	Ref
+	Label     string    `json:"label,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	// Sealed is set while one of the object's proceedings has a seal (plomba).
+	Sealed bool `json:"sealed"`
+}
+
+// Snapshot is what the last successful check saw.
+type Snapshot struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	// Object is the CUZK detail of the object; nil once CUZK answers 404.
+	Object json.RawMessage `json:"object,omitempty"`
+	// Proceedings maps the IDs of the object's proceedings to their status.
+	Proceedings map[int64]string `json:"proceedings"`
+}
+
+// ChangeKind says what changed between two snapshots.
+type ChangeKind string
+
+const (
+	ChangeAttributes        ChangeKind = "attributes_changed"
+	ChangeProceedingStarted ChangeKind = "proceeding_started"
+	ChangeProceedingStatus  ChangeKind = "proceeding_status_changed"
+	ChangeProceedingClosed  ChangeKind = "proceeding_closed" // no longer listed for the object
+	ChangeSealAdded         ChangeKind = "seal_added"
+	ChangeSealRemoved       ChangeKind = "seal_removed"
+	ChangeObjectRemoved     ChangeKind = "object_removed" // CUZK answers 404
+)
+
+// Valid reports whether c is one of the change kinds above.
+func (c ChangeKind) Valid() bool {
+	switch c {
+	case ChangeAttributes, ChangeProceedingStarted, ChangeProceedingStatus, ChangeProceedingClosed,
+		ChangeSealAdded, ChangeSealRemoved, ChangeObjectRemoved:
+		return true
+	}
+	return false
+}
+
+// Event is one recorded change of a watched object.
+type Event struct {
+	Seq int64 `json:"seq"` // assigned by the Store, increasing
+	Ref
+	At            time.Time  `json:"at"`
+	CadastralArea int        `json:"cadastralArea,omitempty"`
+	Change        ChangeKind `json:"change"`
+	// Fields lists the changed attributes (CUZK JSON names) for ChangeAttributes.
+	Fields       []string `json:"fields,omitempty"`
+	ProceedingID int64    `json:"proceedingId,omitempty"`
+	From         string   `json:"from,omitempty"` // previous proceeding status
+	To           string   `json:"to,omitempty"`   // new proceeding status
+}
+
+// EventQuery selects events, newest last.
+type EventQuery struct {
+	Ref      *Ref  // only events of this object when set
+	AfterSeq int64 // only events with a greater Seq
+	Limit    int   // at most this many of the newest matches; 0 means all
+}
+
+// Store persists the watchlist, the last snapshot of each object and the event log.
+type Store interface {
+	Items() ([]Item, error)
+	// Item returns ErrNotFound when ref is not watched.
+	Item(ref Ref) (*Item, error)
+	// PutItem adds or replaces an item.
+	PutItem(item Item) error
+	// UpdateItem applies fn to a stored item; ErrNotFound when ref is not watched.
+	UpdateItem(ref Ref, fn func(*Item)) error
+	// DeleteItem removes an item and its snapshot; ErrNotFound when ref is not watched.
+	DeleteItem(ref Ref) error
+
+	// Snapshot returns nil without error before the first check.
+	Snapshot(ref Ref) (*Snapshot, error)
+	PutSnapshot(ref Ref, s Snapshot) error
+
+	// AppendEvents assigns sequence numbers and stores events, returning them.
+	AppendEvents(events []Event) ([]Event, error)
+	Events(q EventQuery) ([]Event, error)
+}
@@ -0,0 +1,248 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"katastr-p6/backend/internal/watchlist"
+)
+
+// Defaults used by NewDispatcher.
+const (
+	DefaultMaxAttempts = 8
+	DefaultBackoff     = 30 * time.Second
+	DefaultTimeout     = 10 * time.Second
+
+	maxBackoff   = time.Hour
+	pollInterval = 5 * time.Second // how often due retries are looked for
+)
+
+// Option configures a Dispatcher.
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client used for deliveries.
+func WithHTTPClient(c *http.Client) Option {
+	return func(d *Dispatcher) { d.http = c }
+}
+
+// WithRetry sets the number of attempts before a delivery goes to the dead
+// letter state and the delay before the first retry, doubled for each further retry.
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.maxAttempts = max(maxAttempts, 1)
+		d.backoff = backoff
+	}
+}
+
+// Dispatcher turns events into deliveries and sends them.
+type Dispatcher struct {
+	store       Store
+	http        *http.Client
+	maxAttempts int
+	backoff     time.Duration
+
+	wake chan struct{}
+	mu   sync.Mutex // one delivery pass at a time
+}
+
+// NewDispatcher creates a Dispatcher; call Start to send deliveries in the background.
+func NewDispatcher(store Store, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:       store,
+		http:        &http.Client{Timeout: DefaultTimeout},
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultBackoff,
+		wake:        make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Notify queues a delivery of each event to every matching subscription.
+// It implements watchlist.Notifier; sending happens in the Start loop.
+func (d *Dispatcher) Notify(events []watchlist.Event) {
+	subs, err := d.store.Subscriptions()
+	if err != nil {
+		slog.Error("load webhook subscriptions", "error", err)
+		return
+	}
+	now := time.Now()
+	var queued []Delivery
+	for _, e := range events {
+		for _, sub := range subs {
+			if !sub.Filter.Matches(e) {
+				continue
+			}
+			queued = append(queued, Delivery{
+				ID:             NewID(),
+				SubscriptionID: sub.ID,
+				Event:          e,
+				Status:         StatusPending,
+				CreatedAt:      now,
+				NextAttemptAt:  &now,
+			})
+		}
+	}
+	if len(queued) == 0 {
+		return
+	}
+	if err := d.store.PutDeliveries(queued...); err != nil {
+		slog.Error("queue webhook deliveries", "count", len(queued), "error", err)
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends due deliveries in the background until ctx ends.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			d.DeliverDue(ctx)
+			select {
+			case <-ticker.C:
+			case <-d.wake:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// DeliverDue attempts every pending delivery whose next attempt is due and
+// returns how many were attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	due, err := d.store.Deliveries(DeliveryQuery{Status: StatusPending, DueBy: &now})
+	if err != nil {
+		slog.Error("load due webhook deliveries", "error", err)
+		return 0
+	}
+	for i, del := range due {
+		if ctx.Err() != nil {
+			return i
+		}
+		d.attempt(ctx, &del)
+		if ctx.Err() != nil {
+			return i // interrupted by shutdown, not the endpoint's fault; keep it pending
+		}
+		if err := d.store.PutDeliveries(del); err != nil {
+			slog.Error("save webhook delivery", "delivery", del.ID, "error", err)
+		}
+	}
+	return len(due)
+}
+
+// Retry puts a dead or pending delivery back in the queue for an immediate attempt,
+// with a fresh attempt budget.
+func (d *Dispatcher) Retry(id string) (*Delivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	del, err := d.store.Delivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if del.Status == StatusDelivered {
+		return nil, fmt.Errorf("delivery %s was already delivered", id)
+	}
+	now := time.Now()
+	del.Status, del.Attempts, del.NextAttemptAt = StatusPending, 0, &now
+	if err := d.store.PutDeliveries(*del); err != nil {
+		return nil, err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return del, nil
+}
+
+// attempt sends del once and updates its state.
+func (d *Dispatcher) attempt(ctx context.Context, del *Delivery) {
+	sub, err := d.store.Subscription(del.SubscriptionID)
+	if errors.Is(err, ErrNotFound) {
+		del.Status, del.NextAttemptAt, del.LastError = StatusDead, nil, "subscription deleted"
+		return
+	}
+	if err != nil {
+		slog.Error("load webhook subscription", "subscription", del.SubscriptionID, "error", err)
+		return
+	}
+
+	del.Attempts++
+	status, err := d.send(ctx, sub, del)
+	del.LastStatusCode = status
+	if err == nil {
+		now := time.Now()
+		del.Status, del.NextAttemptAt, del.DeliveredAt, del.LastError = StatusDelivered, nil, &now, ""
+		return
+	}
+
+	del.LastError = err.Error()
+	if del.Attempts >= d.maxAttempts {
+		del.Status, del.NextAttemptAt = StatusDead, nil
+		slog.Warn("webhook delivery moved to dead letters", "delivery", del.ID, "subscription", sub.ID, "attempts", del.Attempts, "error", err)
+		return
+	}
+	next := time.Now().Add(d.retryDelay(del.Attempts))
+	del.NextAttemptAt = &next
+	slog.Info("webhook delivery failed, will retry", "delivery", del.ID, "attempt", del.Attempts, "retryAt", next.Format(time.RFC3339), "error", err)
+}
+
+// retryDelay is the wait after the given number of failed attempts: backoff, 2×backoff, 4×… up to maxBackoff.
+func (d *Dispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.backoff
+	for range attempts - 1 {
+		if delay >= maxBackoff/2 {
+			return maxBackoff
+		}
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
+
+// send POSTs the signed payload and returns the response status.
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, del *Delivery) (int, error) {
+	body, err := json.Marshal(Payload{DeliveryID: del.ID, SubscriptionID: sub.ID, Event: del.Event})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "katastr-p6-webhooks")
+	req.Header.Set(SignatureHeader, Sign(sub.Secret, time.Now(), body))
+	req.Header.Set(DeliveryHeader, del.ID)
+	req.Header.Set(EventHeader, string(del.Event.Change))
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
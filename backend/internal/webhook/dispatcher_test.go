@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"katastr-p6/backend/internal/watchlist"
+)
+
+func TestDispatcherRetriesAndDeadLetters(t *testing.T) {
+	const secret = "whsec_test"
+	var calls atomic.Int32
+	var failing atomic.Bool
+	received := make(chan Payload, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := Verify(secret, r.Header.Get(SignatureHeader), body, time.Minute); err != nil {
+			t.Errorf("bad signature: %v", err)
+		}
+		// The first attempt fails, later ones succeed unless failing is set.
+		if calls.Add(1) == 1 || failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var p Payload
+		json.Unmarshal(body, &p)
+		received <- p
+	}))
+	defer receiver.Close()
+
+	ctx := context.Background()
+	store := NewFileStore(filepath.Join(t.TempDir(), "webhooks.json"))
+	store.PutSubscription(Subscription{ID: "s1", URL: receiver.URL, Secret: secret,
+		Filter: Filter{Entities: []watchlist.Kind{watchlist.KindParcel}, Areas: []int{729272}}})
+	d := NewDispatcher(store, WithRetry(2, time.Millisecond))
+
+	d.Notify([]watchlist.Event{
+		{Seq: 1, Ref: watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 729272101}, CadastralArea: 729272, Change: watchlist.ChangeSealAdded},
+		{Seq: 2, Ref: watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 730122101}, CadastralArea: 730122, Change: watchlist.ChangeSealAdded},
+	})
+	if n := d.DeliverDue(ctx); n != 1 {
+		t.Fatalf("expected 1 delivery for the matching area, attempted %d", n)
+	}
+	time.Sleep(5 * time.Millisecond)
+	d.DeliverDue(ctx)
+
+	select {
+	case p := <-received:
+		if p.Event.ObjectID != 729272101 || p.SubscriptionID != "s1" {
+			t.Errorf("unexpected payload %+v", p)
+		}
+	default:
+		t.Fatal("expected the retry to be delivered")
+	}
+	delivered, _ := store.Deliveries(DeliveryQuery{Status: StatusDelivered})
+	if len(delivered) != 1 || delivered[0].Attempts != 2 {
+		t.Fatalf("expected one delivery after 2 attempts, got %+v", delivered)
+	}
+
+	failing.Store(true)
+	d.Notify([]watchlist.Event{{Seq: 3, Ref: watchlist.Ref{Kind: watchlist.KindParcel, ObjectID: 729272101}, CadastralArea: 729272, Change: watchlist.ChangeProceedingStarted}})
+	d.DeliverDue(ctx)
+	time.Sleep(5 * time.Millisecond)
+	d.DeliverDue(ctx)
+	dead, _ := store.Deliveries(DeliveryQuery{Status: StatusDead})
+	if len(dead) != 1 || dead[0].LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected one dead letter, got %+v", dead)
+	}
+
+	failing.Store(false)
+	if _, err := d.Retry(dead[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	d.DeliverDue(ctx)
+	if got, _ := store.Delivery(dead[0].ID); got.Status != StatusDelivered {
+		t.Errorf("expected redelivered dead letter, got %+v", got)
+	}
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// maxFinished bounds how many delivered and dead deliveries a FileStore keeps;
+// the oldest are dropped first. Pending deliveries are always kept.
+const maxFinished = 2000
+
+type fileState struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+	Deliveries    []Delivery     `json:"deliveries"` // oldest first
+}
+
+// FileStore keeps subscriptions and deliveries in a JSON file, rewritten on every change.
+type FileStore struct {
+	path string
+
+	mu     sync.Mutex
+	state  *fileState
+	loaded bool
+}
+
+// NewFileStore creates a FileStore backed by path; the file is created on the first write.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+func (s *FileStore) Subscriptions() ([]Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(st.Subscriptions), nil
+}
+
+func (s *FileStore) Subscription(id string) (*Subscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(st.Subscriptions, func(sub Subscription) bool { return sub.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	sub := st.Subscriptions[i]
+	return &sub, nil
+}
+
+func (s *FileStore) PutSubscription(sub Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(st.Subscriptions, func(x Subscription) bool { return x.ID == sub.ID }); i >= 0 {
+		st.Subscriptions[i] = sub
+	} else {
+		st.Subscriptions = append(st.Subscriptions, sub)
+	}
+	return s.save()
+}
+
+func (s *FileStore) DeleteSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	n := len(st.Subscriptions)
+	st.Subscriptions = slices.DeleteFunc(st.Subscriptions, func(sub Subscription) bool { return sub.ID == id })
+	if len(st.Subscriptions) == n {
+		return ErrNotFound
+	}
+	st.Deliveries = slices.DeleteFunc(st.Deliveries, func(d Delivery) bool {
+		return d.SubscriptionID == id && d.Status == StatusPending
+	})
+	return s.save()
+}
+
+func (s *FileStore) Delivery(id string) (*Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(st.Deliveries, func(d Delivery) bool { return d.ID == id })
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	d := st.Deliveries[i]
+	return &d, nil
+}
+
+func (s *FileStore) PutDeliveries(ds ...Delivery) error {
+	if len(ds) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	for _, d := range ds {
+		if i := slices.IndexFunc(st.Deliveries, func(x Delivery) bool { return x.ID == d.ID }); i >= 0 {
+			st.Deliveries[i] = d
+		} else {
+			st.Deliveries = append(st.Deliveries, d)
+		}
+	}
+	s.trim()
+	return s.save()
+}
+
+func (s *FileStore) Deliveries(q DeliveryQuery) ([]Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	out := []Delivery{}
+	for _, d := range st.Deliveries {
+		if q.Status != "" && d.Status != q.Status {
+			continue
+		}
+		if q.SubscriptionID != "" && d.SubscriptionID != q.SubscriptionID {
+			continue
+		}
+		if q.DueBy != nil && (d.Status != StatusPending || d.NextAttemptAt == nil || d.NextAttemptAt.After(*q.DueBy)) {
+			continue
+		}
+		out = append(out, d)
+	}
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[len(out)-q.Limit:]
+	}
+	return out, nil
+}
+
+// trim drops the oldest finished deliveries beyond maxFinished. Must be called with s.mu held.
+func (s *FileStore) trim() {
+	finished := 0
+	for _, d := range s.state.Deliveries {
+		if d.Status != StatusPending {
+			finished++
+		}
+	}
+	drop := finished - maxFinished
+	if drop <= 0 {
+		return
+	}
+	s.state.Deliveries = slices.DeleteFunc(s.state.Deliveries, func(d Delivery) bool {
+		if drop > 0 && d.Status != StatusPending {
+			drop--
+			return true
+		}
+		return false
+	})
+}
+
+// load reads the file once. Must be called with s.mu held.
+func (s *FileStore) load() (*fileState, error) {
+	if s.loaded {
+		return s.state, nil
+	}
+	st := &fileState{}
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(data, st); err != nil {
+			return nil, err
+		}
+	}
+	s.state, s.loaded = st, true
+	return st, nil
+}
+
+// save writes the state atomically via a temp file and rename. Must be called with s.mu held.
+func (s *FileStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
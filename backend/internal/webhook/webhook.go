@@ -0,0 +1,161 @@
+// Package webhook pushes watchlist change events to subscribed HTTP endpoints
+// with HMAC-signed payloads, retries with exponential backoff and a
+// dead-letter state for deliveries that keep failing.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"katastr-p6/backend/internal/watchlist"
+)
+
+// ErrNotFound is returned by a Store for an unknown subscription or delivery.
+var ErrNotFound = errors.New("webhook: not found")
+
+// Headers set on every delivery.
+const (
+	SignatureHeader = "X-Katastr-Signature" // "t=<unix seconds>,v1=<hex HMAC-SHA256>"
+	DeliveryHeader  = "X-Katastr-Delivery"  // delivery ID, stable across retries
+	EventHeader     = "X-Katastr-Event"     // watchlist.ChangeKind of the payload
+)
+
+// Filter selects the events a subscription receives. Empty lists match everything.
+type Filter struct {
+	Entities []watchlist.Kind       `json:"entities,omitempty"`
+	Areas    []int                  `json:"areas,omitempty"` // cadastral area codes
+	Changes  []watchlist.ChangeKind `json:"changes,omitempty"`
+}
+
+// Matches reports whether e passes the filter.
+func (f Filter) Matches(e watchlist.Event) bool {
+	return (len(f.Entities) == 0 || slices.Contains(f.Entities, e.Kind)) &&
+		(len(f.Areas) == 0 || slices.Contains(f.Areas, e.CadastralArea)) &&
+		(len(f.Changes) == 0 || slices.Contains(f.Changes, e.Change))
+}
+
+// Subscription is an endpoint that receives matching events.
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"` // HMAC key; only shown when the subscription is created
+	Filter    Filter    `json:"filter"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Delivery states.
+const (
+	StatusPending   = "pending"   // waiting for its first attempt or a retry
+	StatusDelivered = "delivered" // the endpoint answered 2xx
+	StatusDead      = "dead"      // gave up after the maximum number of attempts
+)
+
+// Payload is the JSON body POSTed to a subscription.
+type Payload struct {
+	DeliveryID     string          `json:"deliveryId"`
+	SubscriptionID string          `json:"subscriptionId"`
+	Event          watchlist.Event `json:"event"`
+}
+
+// Delivery is one event sent, or to be sent, to one subscription.
+type Delivery struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	Event          watchlist.Event `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	NextAttemptAt  *time.Time      `json:"nextAttemptAt,omitempty"` // set while pending
+	DeliveredAt    *time.Time      `json:"deliveredAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+}
+
+// DeliveryQuery selects deliveries, oldest first.
+type DeliveryQuery struct {
+	Status         string     // only this status when set
+	SubscriptionID string     // only this subscription when set
+	DueBy          *time.Time // only pending deliveries whose next attempt is due by then
+	Limit          int        // at most this many of the newest matches; 0 means all
+}
+
+// Store persists subscriptions and deliveries.
+type Store interface {
+	Subscriptions() ([]Subscription, error)
+	// Subscription returns ErrNotFound for an unknown id.
+	Subscription(id string) (*Subscription, error)
+	PutSubscription(s Subscription) error
+	// DeleteSubscription removes a subscription and its pending deliveries.
+	DeleteSubscription(id string) error
+
+	// Delivery returns ErrNotFound for an unknown id.
+	Delivery(id string) (*Delivery, error)
+	// PutDeliveries adds or replaces deliveries.
+	PutDeliveries(ds ...Delivery) error
+	Deliveries(q DeliveryQuery) ([]Delivery, error)
+}
+
+// Sign returns the SignatureHeader value for body sent at t.
+// The MAC covers "<unix seconds>.<body>" so a captured request cannot be
+// replayed later with a fresh timestamp.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + mac(secret, ts, body)
+}
+
+// Verify checks a SignatureHeader value and rejects signatures older than tolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	var ts, sig string
+	for part := range strings.SplitSeq(header, ",") {
+		k, v, _ := strings.Cut(part, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return errors.New("webhook: malformed signature header")
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook: signature timestamp outside tolerance (%s)", age.Round(time.Second))
+	}
+	if !hmac.Equal([]byte(sig), []byte(mac(secret, ts, body))) {
+		return errors.New("webhook: signature mismatch")
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() string {
+	return "whsec_" + randomHex(24)
+}
+
+// NewID returns a random subscription or delivery ID.
+func NewID() string {
+	return randomHex(8)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}